@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/configutil"
+	latest "github.com/devspace-cloud/devspace/pkg/devspace/config/versions/latest"
+	"github.com/devspace-cloud/devspace/pkg/devspace/kubectl"
+	"github.com/devspace-cloud/devspace/pkg/devspace/services"
+	"github.com/devspace-cloud/devspace/pkg/devspace/services/targetselector"
+	"github.com/devspace-cloud/devspace/pkg/util/log"
+	"github.com/spf13/cobra"
+)
+
+// CpCmd is a struct that defines a command call for "cp"
+type CpCmd struct {
+	Selector      string
+	Namespace     string
+	LabelSelector string
+	Container     string
+	Pick          bool
+
+	Exclude []string
+}
+
+// NewCpCmd creates a new cp command
+func NewCpCmd() *cobra.Command {
+	cmd := &CpCmd{}
+
+	cpCmd := &cobra.Command{
+		Use:   "cp",
+		Short: "Copies files and folders to and from a container",
+		Long: `
+#######################################################
+#################### devspace cp ######################
+#######################################################
+Copies files and folders between the local machine and
+a container. Container paths have the form [POD]:PATH,
+if the pod is omitted, the pod is selected via the
+selector flags:
+
+devspace cp ./dist my-pod:/app/dist
+devspace cp :/tmp/core.dump . --selector=default
+devspace cp :/app/reports ./reports -c test -e '*.log'
+#######################################################`,
+		Args: cobra.ExactArgs(2),
+		Run:  cmd.Run,
+	}
+
+	cpCmd.Flags().StringVarP(&cmd.Selector, "selector", "s", "", "Selector name (in config) to select pod/container")
+	cpCmd.Flags().StringVarP(&cmd.Container, "container", "c", "", "Container name within pod to copy from or to")
+	cpCmd.Flags().StringVarP(&cmd.LabelSelector, "label-selector", "l", "", "Comma separated key=value selector list (e.g. release=test)")
+	cpCmd.Flags().StringVarP(&cmd.Namespace, "namespace", "n", "", "Namespace where to select pods")
+	cpCmd.Flags().BoolVarP(&cmd.Pick, "pick", "p", false, "Select a pod to copy from or to")
+
+	cpCmd.Flags().StringSliceVarP(&cmd.Exclude, "exclude", "e", []string{}, "Exclude paths from copying (gitignore syntax)")
+
+	return cpCmd
+}
+
+// Run executes the command logic
+func (cmd *CpCmd) Run(cobraCmd *cobra.Command, args []string) {
+	sourcePod, sourcePath, sourceRemote := parseCopyTarget(args[0])
+	targetPod, targetPath, targetRemote := parseCopyTarget(args[1])
+	if sourceRemote == targetRemote {
+		log.Fatal("Exactly one of source and destination has to be a container path ([POD]:PATH)")
+	}
+
+	// Set config root
+	_, err := configutil.SetDevSpaceRoot()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var config *latest.Config
+	if configutil.ConfigExists() {
+		config = configutil.GetConfig()
+	}
+
+	// Get kubectl client
+	kubectl, err := kubectl.NewClient(config)
+	if err != nil {
+		log.Fatalf("Unable to create new kubectl client: %v", err)
+	}
+
+	// Build params
+	params := targetselector.CmdParameter{}
+	if cmd.Selector != "" {
+		params.Selector = &cmd.Selector
+	}
+	if cmd.Container != "" {
+		params.ContainerName = &cmd.Container
+	}
+	if cmd.LabelSelector != "" {
+		params.LabelSelector = &cmd.LabelSelector
+	}
+	if cmd.Namespace != "" {
+		params.Namespace = &cmd.Namespace
+	}
+	if cmd.Pick != false {
+		params.Pick = &cmd.Pick
+	}
+
+	if targetRemote {
+		if targetPod != "" {
+			params.PodName = &targetPod
+		}
+
+		err = services.CopyFromCmd(config, kubectl, params, sourcePath, targetPath, true, cmd.Exclude, log.GetInstance())
+	} else {
+		if sourcePod != "" {
+			params.PodName = &sourcePod
+		}
+
+		err = services.CopyFromCmd(config, kubectl, params, targetPath, sourcePath, false, cmd.Exclude, log.GetInstance())
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// parseCopyTarget splits a copy argument of the form [POD]:PATH into pod and path. Arguments without a colon
+// and windows paths with a drive letter (e.g. C:\dir) are local paths
+func parseCopyTarget(arg string) (string, string, bool) {
+	index := strings.Index(arg, ":")
+	if index == -1 || index == 1 {
+		return "", arg, false
+	}
+
+	return arg[:index], arg[index+1:], true
+}
@@ -30,8 +30,9 @@ type DeployCmd struct {
 	Deployments       string
 	ForceDependencies bool
 
-	SwitchContext bool
-	SkipPush      bool
+	SwitchContext  bool
+	SkipPush       bool
+	SkipGuardrails bool
 
 	AllowCyclicDependencies bool
 }
@@ -64,6 +65,7 @@ devspace deploy --kube-context=deploy-context
 	deployCmd.Flags().StringVar(&cmd.KubeContext, "kube-context", "", "The kubernetes context to use for deployment")
 
 	deployCmd.Flags().BoolVar(&cmd.SwitchContext, "switch-context", false, "Switches the kube context to the deploy context")
+	deployCmd.Flags().BoolVar(&cmd.SkipGuardrails, "i-know-what-im-doing", false, "Skips the allowed contexts, allowed namespaces and protected checks of the cluster config")
 	deployCmd.Flags().BoolVar(&cmd.SkipPush, "skip-push", false, "Skips image pushing, useful for minikube deployment")
 
 	deployCmd.Flags().BoolVarP(&cmd.ForceBuild, "force-build", "b", false, "Forces to (re-)build every image")
@@ -99,7 +101,7 @@ func (cmd *DeployCmd) Run(cobraCmd *cobra.Command, args []string) {
 	config := cmd.loadConfig(generatedConfig)
 
 	// Create kubectl client
-	client, err := kubectl.NewClientWithContextSwitch(config, cmd.SwitchContext)
+	client, err := kubectl.NewClientWithContextSwitch(config, cmd.SwitchContext, cmd.SkipGuardrails)
 	if err != nil {
 		log.Fatalf("Unable to create new kubectl client: %v", err)
 	}
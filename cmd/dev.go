@@ -42,6 +42,7 @@ type DevCmd struct {
 	ExitAfterDeploy bool
 	SkipPipeline    bool
 	SwitchContext   bool
+	SkipGuardrails  bool
 	Portforwarding  bool
 	VerboseSync     bool
 	Selector        string
@@ -96,6 +97,7 @@ Starts your project in development mode:
 	devCmd.Flags().StringVarP(&cmd.Namespace, "namespace", "n", "", "The namespace to deploy to")
 
 	devCmd.Flags().BoolVar(&cmd.SwitchContext, "switch-context", false, "Switch kubectl context to the DevSpace context")
+	devCmd.Flags().BoolVar(&cmd.SkipGuardrails, "i-know-what-im-doing", false, "Skips the allowed contexts, allowed namespaces and protected checks of the cluster config")
 	devCmd.Flags().BoolVar(&cmd.ExitAfterDeploy, "exit-after-deploy", false, "Exits the command after building the images and deploying the project")
 
 	return devCmd
@@ -125,7 +127,7 @@ func (cmd *DevCmd) Run(cobraCmd *cobra.Command, args []string) {
 	config := cmd.loadConfig(generatedConfig)
 
 	// Create kubectl client and switch context if specified
-	client, err := kubectl.NewClientWithContextSwitch(config, cmd.SwitchContext)
+	client, err := kubectl.NewClientWithContextSwitch(config, cmd.SwitchContext, cmd.SkipGuardrails)
 	if err != nil {
 		log.Fatalf("Unable to create new kubectl client: %v", err)
 	}
@@ -237,7 +239,7 @@ func (cmd *DevCmd) startServices(config *latest.Config, client kubernetes.Interf
 
 		defer func() {
 			for _, v := range portForwarder {
-				v.Close()
+				v.Stop()
 			}
 		}()
 	}
@@ -250,7 +252,7 @@ func (cmd *DevCmd) startServices(config *latest.Config, client kubernetes.Interf
 
 		defer func() {
 			for _, v := range syncConfigs {
-				v.Stop(nil)
+				v.Stop()
 			}
 		}()
 	}
@@ -71,8 +71,8 @@ func (cmd *EnterCmd) Run(cobraCmd *cobra.Command, args []string) {
 		config = configutil.GetConfig()
 	}
 
-	// Get kubectl client
-	kubectl, err := kubectl.NewClientWithContextSwitch(config, cmd.SwitchContext)
+	// Get kubectl client. Entering a container doesn't change any resources, so the cluster guardrails don't apply
+	kubectl, err := kubectl.NewClientWithContextSwitch(config, cmd.SwitchContext, true)
 	if err != nil {
 		log.Fatalf("Unable to create new kubectl client: %v", err)
 	}
@@ -0,0 +1,83 @@
+package list
+
+import (
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/configutil"
+	"github.com/devspace-cloud/devspace/pkg/util/log"
+	"github.com/spf13/cobra"
+)
+
+type commandsCmd struct{}
+
+func newCommandsCmd() *cobra.Command {
+	cmd := &commandsCmd{}
+
+	commandsCmd := &cobra.Command{
+		Use:   "commands",
+		Short: "Lists all custom DevSpace commands",
+		Long: `
+#######################################################
+############## devspace list commands #################
+#######################################################
+Lists the commands that can be executed with
+devspace run
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+		Run:  cmd.RunListCommands,
+	}
+
+	return commandsCmd
+}
+
+// RunListCommands runs the list commands command logic
+func (cmd *commandsCmd) RunListCommands(cobraCmd *cobra.Command, args []string) {
+	// Set config root
+	configExists, err := configutil.SetDevSpaceRoot()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if !configExists {
+		log.Fatal("Couldn't find a DevSpace configuration. Please run `devspace init`")
+	}
+
+	config := configutil.GetConfig()
+
+	if config.Commands == nil || len(*config.Commands) == 0 {
+		log.Info("No commands are defined. Add commands to the commands section of your DevSpace configuration\n")
+		return
+	}
+
+	headerColumnNames := []string{
+		"Name",
+		"Target",
+		"Command",
+		"Description",
+	}
+
+	commands := make([][]string, 0, len(*config.Commands))
+
+	// Transform values into string arrays
+	for _, value := range *config.Commands {
+		target := "local"
+		if value.Selector != nil {
+			target = *value.Selector
+			if value.ContainerName != nil {
+				target += ":" + *value.ContainerName
+			}
+		}
+
+		description := ""
+		if value.Description != nil {
+			description = *value.Description
+		}
+
+		commands = append(commands, []string{
+			*value.Name,
+			target,
+			*value.Command,
+			description,
+		})
+	}
+
+	log.PrintTable(headerColumnNames, commands)
+}
@@ -3,6 +3,7 @@ package list
 import (
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/devspace-cloud/devspace/pkg/devspace/config/configs"
 	"github.com/devspace-cloud/devspace/pkg/devspace/config/configutil"
@@ -72,6 +73,7 @@ func (cmd *configsCmd) RunListConfigs(cobraCmd *cobra.Command, args []string) {
 		"Path",
 		"Vars",
 		"Overwrites",
+		"Profiles",
 	}
 
 	configRows := make([][]string, 0, len(configs))
@@ -87,12 +89,22 @@ func (cmd *configsCmd) RunListConfigs(cobraCmd *cobra.Command, args []string) {
 			overrides = len(*config.Overrides)
 		}
 
+		profiles := []string{}
+		if config.Profiles != nil {
+			for _, profile := range *config.Profiles {
+				if profile.Name != nil {
+					profiles = append(profiles, *profile.Name)
+				}
+			}
+		}
+
 		configRows = append(configRows, []string{
 			configName,
 			strconv.FormatBool(configName == generatedConfig.ActiveConfig),
 			path,
 			strconv.FormatBool(config.Vars != nil),
 			strconv.Itoa(overrides),
+			strings.Join(profiles, ", "),
 		})
 	}
 
@@ -52,6 +52,7 @@ func (cmd *portsCmd) RunListPort(cobraCmd *cobra.Command, args []string) {
 		"Selector",
 		"LabelSelector",
 		"Ports (Local:Remote)",
+		"Reverse Ports (Local:Remote)",
 	}
 
 	portForwards := make([][]string, 0, len(*config.Dev.Ports))
@@ -63,7 +64,9 @@ func (cmd *portsCmd) RunListPort(cobraCmd *cobra.Command, args []string) {
 
 		if value.Selector != nil {
 			service = *value.Selector
-		} else {
+		} else if value.Service != nil {
+			service = "service/" + *value.Service
+		} else if value.LabelSelector != nil {
 			for k, v := range *value.LabelSelector {
 				if len(selector) > 0 {
 					selector += ", "
@@ -80,7 +83,35 @@ func (cmd *portsCmd) RunListPort(cobraCmd *cobra.Command, args []string) {
 					portMappings += ", "
 				}
 
-				portMappings += strconv.Itoa(*v.LocalPort) + ":" + strconv.Itoa(*v.RemotePort)
+				remotePort := *v.LocalPort
+				if v.RemotePort != nil {
+					remotePort = *v.RemotePort
+				}
+
+				portMappings += strconv.Itoa(*v.LocalPort) + ":" + strconv.Itoa(remotePort)
+			}
+		}
+		if value.ForwardContainerPorts != nil && *value.ForwardContainerPorts {
+			if len(portMappings) > 0 {
+				portMappings += ", "
+			}
+
+			portMappings += "container ports"
+		}
+
+		reverseForwards := ""
+		if value.ReverseForward != nil {
+			for _, v := range *value.ReverseForward {
+				if len(reverseForwards) > 0 {
+					reverseForwards += ", "
+				}
+
+				remotePort := *v.LocalPort
+				if v.RemotePort != nil {
+					remotePort = *v.RemotePort
+				}
+
+				reverseForwards += strconv.Itoa(*v.LocalPort) + ":" + strconv.Itoa(remotePort)
 			}
 		}
 
@@ -88,6 +119,7 @@ func (cmd *portsCmd) RunListPort(cobraCmd *cobra.Command, args []string) {
 			service,
 			selector,
 			portMappings,
+			reverseForwards,
 		})
 	}
 
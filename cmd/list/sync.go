@@ -1,7 +1,11 @@
 package list
 
 import (
+	"path/filepath"
+	"strings"
+
 	"github.com/devspace-cloud/devspace/pkg/devspace/config/configutil"
+	"github.com/devspace-cloud/devspace/pkg/devspace/services"
 	"github.com/devspace-cloud/devspace/pkg/util/log"
 	"github.com/spf13/cobra"
 )
@@ -72,18 +76,24 @@ func (cmd *syncCmd) RunListSync(cobraCmd *cobra.Command, args []string) {
 				selector += k + "=" + *v
 			}
 		}
-		excludedPaths := ""
+		localPath := "."
+		if value.LocalSubPath != nil {
+			localPath = *value.LocalSubPath
+		}
 
-		if value.ExcludePaths != nil {
-			for _, v := range *value.ExcludePaths {
-				if len(excludedPaths) > 0 {
-					excludedPaths += ", "
-				}
+		absLocalPath, err := filepath.Abs(localPath)
+		if err != nil {
+			log.Fatalf("Unable to resolve localSubPath %s: %v", localPath, err)
+		}
 
-				excludedPaths += v
-			}
+		// Show the effective exclude paths including .gitignore and .dockerignore rules
+		excludePaths, err := services.GetExcludePaths(value, absLocalPath)
+		if err != nil {
+			log.Fatal(err)
 		}
 
+		excludedPaths := strings.Join(excludePaths, ", ")
+
 		syncPaths = append(syncPaths, []string{
 			service,
 			selector,
@@ -52,8 +52,11 @@ func (cmd *configsCmd) RunListVars(cobraCmd *cobra.Command, args []string) {
 		log.Fatal(err)
 	}
 
+	// Values of secret variables are never printed
+	secretVars := configutil.GetSecretVars()
+
 	// No variable found
-	if generatedConfig.GetActive().Vars == nil || len(generatedConfig.GetActive().Vars) == 0 {
+	if len(generatedConfig.GetActive().Vars) == 0 && len(secretVars) == 0 {
 		log.Infof("No variable found for config %s", generatedConfig.ActiveConfig)
 		return
 	}
@@ -64,7 +67,7 @@ func (cmd *configsCmd) RunListVars(cobraCmd *cobra.Command, args []string) {
 		"Value",
 	}
 
-	varRow := make([][]string, 0, len(generatedConfig.GetActive().Vars))
+	varRow := make([][]string, 0, len(generatedConfig.GetActive().Vars)+len(secretVars))
 
 	for name, value := range generatedConfig.GetActive().Vars {
 		varRow = append(varRow, []string{
@@ -72,6 +75,12 @@ func (cmd *configsCmd) RunListVars(cobraCmd *cobra.Command, args []string) {
 			fmt.Sprintf("%v", value),
 		})
 	}
+	for _, name := range secretVars {
+		varRow = append(varRow, []string{
+			name,
+			log.RedactedValue,
+		})
+	}
 
 	log.PrintTable(headerColumnNames, varRow)
 }
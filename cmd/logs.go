@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	"regexp"
+	"time"
+
 	"github.com/devspace-cloud/devspace/pkg/devspace/config/configutil"
 	latest "github.com/devspace-cloud/devspace/pkg/devspace/config/versions/latest"
 	"github.com/devspace-cloud/devspace/pkg/devspace/kubectl"
@@ -20,6 +23,13 @@ type LogsCmd struct {
 	Pick              bool
 	Follow            bool
 	LastAmountOfLines int
+
+	All        bool
+	Deployment string
+	Since      time.Duration
+	Include    string
+	Exclude    string
+	JSON       bool
 }
 
 // NewLogsCmd creates a new login command
@@ -39,6 +49,8 @@ to it
 Example:
 devspace logs
 devspace logs --namespace=mynamespace
+devspace logs --all -f
+devspace logs --deployment=api --since=10m --include=ERROR
 #######################################################
 	`,
 		Args: cobra.NoArgs,
@@ -54,6 +66,13 @@ devspace logs --namespace=mynamespace
 	logsCmd.Flags().BoolVarP(&cmd.Follow, "follow", "f", false, "Attach to logs afterwards")
 	logsCmd.Flags().IntVar(&cmd.LastAmountOfLines, "lines", 200, "Max amount of lines to print from the last log")
 
+	logsCmd.Flags().BoolVar(&cmd.All, "all", false, "Print the logs of all pods and containers matching the selector concurrently")
+	logsCmd.Flags().StringVar(&cmd.Deployment, "deployment", "", "Print the logs of all pods of the given kubernetes deployment concurrently")
+	logsCmd.Flags().DurationVar(&cmd.Since, "since", 0, "Only print logs newer than the given duration (e.g. 10m)")
+	logsCmd.Flags().StringVar(&cmd.Include, "include", "", "Only print lines matching the given regex")
+	logsCmd.Flags().StringVar(&cmd.Exclude, "exclude", "", "Don't print lines matching the given regex")
+	logsCmd.Flags().BoolVar(&cmd.JSON, "json", false, "Print every line as json object with pod and container fields")
+
 	return logsCmd
 }
 
@@ -97,6 +116,45 @@ func (cmd *LogsCmd) RunLogs(cobraCmd *cobra.Command, args []string) {
 		params.Pick = &cmd.Pick
 	}
 
+	// Print the logs of all matching pods and containers
+	multiLogs := cmd.All || cmd.Deployment != ""
+	if multiLogs || cmd.Since > 0 || cmd.Include != "" || cmd.Exclude != "" || cmd.JSON {
+		if multiLogs && cmd.Pick {
+			log.Fatal("Flag --pick cannot be used together with --all or --deployment")
+		}
+
+		options := &services.LogOptions{
+			Follow: cmd.Follow,
+			Tail:   int64(cmd.LastAmountOfLines),
+			Since:  cmd.Since,
+			JSON:   cmd.JSON,
+		}
+		if cmd.Include != "" {
+			options.Include, err = regexp.Compile(cmd.Include)
+			if err != nil {
+				log.Fatalf("Invalid include regex %s: %v", cmd.Include, err)
+			}
+		}
+		if cmd.Exclude != "" {
+			options.Exclude, err = regexp.Compile(cmd.Exclude)
+			if err != nil {
+				log.Fatalf("Invalid exclude regex %s: %v", cmd.Exclude, err)
+			}
+		}
+
+		if multiLogs {
+			err = services.StartMultiLogs(config, kubectl, params, cmd.Deployment, options, log.GetInstance())
+		} else {
+			// The filters are applied to the logs of the selected pod only
+			err = services.StartFilteredLogs(config, kubectl, params, options, log.GetInstance())
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		return
+	}
+
 	// Start terminal
 	err = services.StartLogs(config, kubectl, params, cmd.Follow, int64(cmd.LastAmountOfLines), log.GetInstance())
 	if err != nil {
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/configutil"
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/generated"
+	"github.com/devspace-cloud/devspace/pkg/devspace/dependency"
+	"github.com/devspace-cloud/devspace/pkg/util/log"
+	"github.com/devspace-cloud/devspace/pkg/util/yamlutil"
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// PrintCmd holds the flags of the print command
+type PrintCmd struct {
+	Config       string
+	ShowOrigin   bool
+	Dependencies bool
+
+	AllowCyclicDependencies bool
+}
+
+// NewPrintCmd creates a new print command
+func NewPrintCmd() *cobra.Command {
+	cmd := &PrintCmd{}
+
+	printCmd := &cobra.Command{
+		Use:   "print",
+		Short: "Prints the resolved configuration",
+		Long: `
+#######################################################
+#################### devspace print ###################
+#######################################################
+Prints the fully resolved configuration with imports,
+overrides, profiles and variables applied:
+
+devspace print
+devspace print --config=production
+devspace print --show-origin
+devspace print --dependencies
+#######################################################`,
+		Args: cobra.NoArgs,
+		Run:  cmd.Run,
+	}
+
+	printCmd.Flags().StringVar(&cmd.Config, "config", "", "The config to print (defaults to the active config)")
+	printCmd.Flags().BoolVar(&cmd.ShowOrigin, "show-origin", false, "Shows where each value came from (base, imports, overrides, profiles and variables)")
+	printCmd.Flags().BoolVar(&cmd.Dependencies, "dependencies", false, "Prints the resolved configs of all dependencies as well")
+	printCmd.Flags().BoolVar(&cmd.AllowCyclicDependencies, "allow-cyclic", false, "When enabled allows cyclic dependencies")
+
+	return printCmd
+}
+
+// Run executes the command logic
+func (cmd *PrintCmd) Run(cobraCmd *cobra.Command, args []string) {
+	// Set config root
+	configExists, err := configutil.SetDevSpaceRoot()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if !configExists {
+		log.Fatal("Couldn't find a DevSpace configuration. Please run `devspace init`")
+	}
+
+	generatedConfig, err := generated.LoadConfig()
+	if err != nil {
+		log.Fatalf("Error loading generated.yaml: %v", err)
+	}
+
+	loadConfig := generatedConfig.ActiveConfig
+	if cmd.Config != "" {
+		loadConfig = cmd.Config
+	}
+
+	// Loading messages would end up in the printed yaml
+	config, origins, err := configutil.GetConfigWithOrigins(".", loadConfig, generatedConfig, log.Discard)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	out, err := yaml.Marshal(config)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if cmd.ShowOrigin {
+		out = annotateOrigins(out, origins)
+	}
+
+	// Secret variables are redacted
+	log.WriteString(string(out))
+
+	if cmd.Dependencies && config.Dependencies != nil {
+		resolver, err := dependency.NewResolver(config, generatedConfig, cmd.AllowCyclicDependencies, log.Discard)
+		if err != nil {
+			log.Fatalf("Error creating dependency resolver: %v", err)
+		}
+
+		dependencies, err := resolver.Resolve(*config.Dependencies, false)
+		if err != nil {
+			log.Fatalf("Error resolving dependencies: %v", err)
+		}
+
+		for _, dependency := range dependencies {
+			out, err := yaml.Marshal(dependency.Config)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			log.WriteString(fmt.Sprintf("---\n# Dependency %s (%s)\n%s", dependency.ID, dependency.LocalPath, string(out)))
+		}
+	}
+}
+
+// annotateOrigins appends the origin of every value as comment to its line
+func annotateOrigins(out []byte, origins []*configutil.ValueOrigin) []byte {
+	lines := strings.Split(string(out), "\n")
+	comments := map[int][]string{}
+
+	for _, origin := range origins {
+		comment := origin.Stage
+		if origin.Var != "" {
+			if comment != "" {
+				comment += ", "
+			}
+
+			comment += "var " + origin.Var
+		}
+		if comment == "" {
+			continue
+		}
+
+		line, _ := yamlutil.FindPosition(out, origin.Path)
+		if line == 0 {
+			continue
+		}
+
+		// Several values can be located on the same line (e.g. list items)
+		if len(comments[line-1]) == 0 || comments[line-1][len(comments[line-1])-1] != comment {
+			comments[line-1] = append(comments[line-1], comment)
+		}
+	}
+
+	for index, lineComments := range comments {
+		lines[index] += " # " + strings.Join(lineComments, "; ")
+	}
+
+	return []byte(strings.Join(lines, "\n"))
+}
@@ -21,6 +21,7 @@ type PurgeCmd struct {
 	Namespace               string
 	AllowCyclicDependencies bool
 	PurgeDependencies       bool
+	SkipGuardrails          bool
 }
 
 // NewPurgeCmd creates a new purge command
@@ -48,6 +49,7 @@ devspace purge -d my-deployment
 	purgeCmd.Flags().StringVarP(&cmd.Deployments, "deployments", "d", "", "The deployment to delete (You can specify multiple deployments comma-separated, e.g. devspace-default,devspace-database etc.)")
 	purgeCmd.Flags().BoolVar(&cmd.AllowCyclicDependencies, "allow-cyclic", false, "When enabled allows cyclic dependencies")
 	purgeCmd.Flags().BoolVar(&cmd.PurgeDependencies, "dependencies", false, "When enabled purges the dependencies as well")
+	purgeCmd.Flags().BoolVar(&cmd.SkipGuardrails, "i-know-what-im-doing", false, "Skips the allowed contexts, allowed namespaces and protected checks of the cluster config")
 
 	return purgeCmd
 }
@@ -74,7 +76,7 @@ func (cmd *PurgeCmd) Run(cobraCmd *cobra.Command, args []string) {
 	// Get the config
 	config := cmd.loadConfig(generatedConfig)
 
-	kubectl, err := kubectl.NewClient(config)
+	kubectl, err := kubectl.NewClientWithContextSwitch(config, false, cmd.SkipGuardrails)
 	if err != nil {
 		log.Fatalf("Unable to create new kubectl client: %v", err)
 	}
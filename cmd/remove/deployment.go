@@ -12,7 +12,8 @@ import (
 )
 
 type deploymentCmd struct {
-	RemoveAll bool
+	RemoveAll      bool
+	SkipGuardrails bool
 }
 
 func newDeploymentCmd() *cobra.Command {
@@ -38,6 +39,7 @@ devspace remove deployment --all
 	}
 
 	deploymentCmd.Flags().BoolVar(&cmd.RemoveAll, "all", false, "Remove all deployments")
+	deploymentCmd.Flags().BoolVar(&cmd.SkipGuardrails, "i-know-what-im-doing", false, "Skips the allowed contexts, allowed namespaces and protected checks of the cluster config")
 
 	return deploymentCmd
 }
@@ -70,7 +72,7 @@ func (cmd *deploymentCmd) RunRemoveDeployment(cobraCmd *cobra.Command, args []st
 		},
 	}) == "yes"
 	if shouldPurgeDeployment {
-		kubectl, err := kubectl.NewClient(config)
+		kubectl, err := kubectl.NewClientWithContextSwitch(config, false, cmd.SkipGuardrails)
 		if err != nil {
 			log.Fatalf("Unable to create new kubectl client: %v", err)
 		}
@@ -67,6 +67,10 @@ func init() {
 	rootCmd.AddCommand(NewInitCmd())
 	rootCmd.AddCommand(NewDevCmd())
 	rootCmd.AddCommand(NewSyncCmd())
+	rootCmd.AddCommand(NewCpCmd())
+	rootCmd.AddCommand(NewRunCmd())
+	rootCmd.AddCommand(NewValidateCmd())
+	rootCmd.AddCommand(NewPrintCmd())
 	rootCmd.AddCommand(NewInstallCmd())
 	rootCmd.AddCommand(NewPurgeCmd())
 	rootCmd.AddCommand(NewUpgradeCmd())
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/commands"
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/configutil"
+	"github.com/devspace-cloud/devspace/pkg/util/log"
+	"github.com/spf13/cobra"
+)
+
+// RunCmd is a struct that defines a command call for "run"
+type RunCmd struct{}
+
+// NewRunCmd creates a new run command
+func NewRunCmd() *cobra.Command {
+	cmd := &RunCmd{}
+
+	runCmd := &cobra.Command{
+		Use:   "run",
+		Short: "Runs a command defined in the DevSpace configuration",
+		Long: `
+#######################################################
+##################### devspace run ####################
+#######################################################
+Runs a command that is defined in the commands section
+of the DevSpace configuration. Additional arguments are
+appended to the command:
+
+devspace run migrate
+devspace run test --verbose
+#######################################################`,
+		Args: cobra.MinimumNArgs(1),
+		Run:  cmd.Run,
+	}
+
+	// All flags after the command name are passed to the command
+	runCmd.Flags().SetInterspersed(false)
+
+	return runCmd
+}
+
+// Run executes the command logic
+func (cmd *RunCmd) Run(cobraCmd *cobra.Command, args []string) {
+	// Set config root
+	configExists, err := configutil.SetDevSpaceRoot()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if !configExists {
+		log.Fatal("Couldn't find a DevSpace configuration. Please run `devspace init`")
+	}
+
+	config := configutil.GetConfig()
+
+	err = commands.ExecuteCommand(config, args[0], args[1:], log.GetInstance())
+	if err != nil {
+		if exitCodeError, ok := err.(*commands.ExitCodeError); ok {
+			os.Exit(exitCodeError.ExitCode)
+		}
+
+		log.Fatal(err)
+	}
+}
@@ -1,44 +1,46 @@
 package status
 
 import (
+	"bufio"
 	"encoding/json"
-	"io/ioutil"
+	"fmt"
+	"io"
 	"math"
 	"os"
 	"path/filepath"
-	"regexp"
+	"sort"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/devspace-cloud/devspace/pkg/devspace/config/configutil"
+	"github.com/devspace-cloud/devspace/pkg/devspace/sync"
 	"github.com/devspace-cloud/devspace/pkg/util/log"
 	"github.com/spf13/cobra"
 )
 
-var syncStopped = regexp.MustCompile(`^\[Sync\] Sync stopped$`)
-var downstreamChanges = regexp.MustCompile(`^\[Downstream\] Successfully processed (\d+) change\(s\)$`)
-var upstreamChanges = regexp.MustCompile(`^\[Upstream\] Successfully processed (\d+) change\(s\)$`)
-
 type syncStatus struct {
-	Status    string
-	Pod       string
-	Local     string
-	Container string
+	Status    string `json:"status"`
+	Pod       string `json:"pod"`
+	Local     string `json:"local"`
+	Container string `json:"container"`
 
-	LastActivity     string
-	LastActivityTime string
-	Error            string
+	LastActivity     string    `json:"lastActivity"`
+	LastActivityTime time.Time `json:"lastActivityTime"`
+	Error            string    `json:"error,omitempty"`
 
-	TotalChanges int
+	TotalChanges int   `json:"totalChanges"`
+	TotalBytes   int64 `json:"totalBytes"`
 }
 
-type syncCmd struct{}
+type syncCmd struct {
+	Watch bool
+	JSON  bool
+}
 
 func newSyncCmd() *cobra.Command {
 	cmd := &syncCmd{}
 
-	return &cobra.Command{
+	syncCmd := &cobra.Command{
 		Use:   "sync",
 		Short: "Shows the sync status",
 		Long: `
@@ -46,11 +48,21 @@ func newSyncCmd() *cobra.Command {
 ################ devspace status sync #################
 #######################################################
 Shows the sync status
+
+Example:
+devspace status sync
+devspace status sync --watch
+devspace status sync --json
 #######################################################
 	`,
 		Args: cobra.NoArgs,
 		Run:  cmd.RunStatusSync,
 	}
+
+	syncCmd.Flags().BoolVarP(&cmd.Watch, "watch", "w", false, "Keep watching the sync status")
+	syncCmd.Flags().BoolVar(&cmd.JSON, "json", false, "Print the sync status as json (with --watch every sync event is printed as json line)")
+
+	return syncCmd
 }
 
 // RunStatusSync executes the devspace status sync commad logic
@@ -64,41 +76,162 @@ func (cmd *syncCmd) RunStatusSync(cobraCmd *cobra.Command, args []string) {
 		log.Fatal("Couldn't find a DevSpace configuration. Please run `devspace init`")
 	}
 
-	// Read syncLog
+	// Open sync event log
 	cwd, err := os.Getwd()
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	syncLogPath := filepath.Join(cwd, ".devspace", "logs", "sync.log")
-	data, err := ioutil.ReadFile(syncLogPath)
+	eventLogPath := filepath.Join(cwd, ".devspace", "logs", sync.EventLogName)
+	reader := &eventReader{path: eventLogPath}
+	err = reader.open()
 	if err != nil {
-		log.Fatalf("Couldn't read %s. Do you have a sync path configured? (check `devspace list sync`)", syncLogPath)
+		log.Fatalf("Couldn't read %s. Do you have a sync path configured? (check `devspace list sync`)", eventLogPath)
 	}
 
+	defer reader.file.Close()
+
 	syncMap := make(map[string]*syncStatus)
-	lines := strings.Split(string(data), "\n")
 
-	for _, line := range lines {
-		if line == "" {
-			continue
+	for {
+		events, restarted, err := reader.readEvents()
+		if err != nil {
+			log.Fatalf("Error parsing %s: %v", eventLogPath, err)
+		}
+		if restarted {
+			// A new sync session truncated the event log
+			syncMap = make(map[string]*syncStatus)
+		}
+
+		for _, event := range events {
+			updateSyncMap(syncMap, event)
+
+			if cmd.Watch && cmd.JSON {
+				out, _ := json.Marshal(event)
+				fmt.Println(string(out))
+			}
 		}
 
-		jsonMap := make(map[string]string)
-		err = json.Unmarshal([]byte(line), &jsonMap)
+		if cmd.Watch == false {
+			break
+		} else if len(events) > 0 && cmd.JSON == false {
+			// Clear the screen and print the updated table
+			fmt.Print("\033[H\033[2J")
+			printSyncStatus(syncMap)
+		}
+
+		time.Sleep(time.Second)
+	}
+
+	if cmd.JSON {
+		out, err := json.MarshalIndent(getSortedStatus(syncMap), "", "  ")
 		if err != nil {
 			log.Fatal(err)
 		}
-		if isSyncJSONMapInvalid(jsonMap) {
-			log.Fatalf("Error parsing %s: Json object is invalid %s", syncLogPath, line)
+
+		fmt.Println(string(out))
+		return
+	}
+
+	printSyncStatus(syncMap)
+}
+
+// eventReader reads sync events from the event log while it is written
+type eventReader struct {
+	path    string
+	file    *os.File
+	reader  *bufio.Reader
+	offset  int64
+	pending string
+}
+
+// open (re)opens the event log and starts reading from the beginning
+func (e *eventReader) open() error {
+	file, err := os.Open(e.path)
+	if err != nil {
+		return err
+	}
+
+	if e.file != nil {
+		e.file.Close()
+	}
+
+	e.file = file
+	e.reader = bufio.NewReader(file)
+	e.offset = 0
+	e.pending = ""
+	return nil
+}
+
+// restarted checks if the event log was truncated or replaced by a new sync session since the last read
+func (e *eventReader) restarted() bool {
+	stat, err := os.Stat(e.path)
+	if err != nil {
+		return false
+	}
+
+	openStat, err := e.file.Stat()
+	if err != nil {
+		return true
+	}
+
+	return os.SameFile(stat, openStat) == false || stat.Size() < e.offset
+}
+
+// readEvents reads all complete event lines that are currently available. The second return value is true
+// if the event log was restarted and the events of the previous session have to be discarded
+func (e *eventReader) readEvents() ([]*sync.Event, bool, error) {
+	events := []*sync.Event{}
+
+	restarted := e.restarted()
+	if restarted {
+		err := e.open()
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	for {
+		line, err := e.reader.ReadString('\n')
+		e.offset += int64(len(line))
+		if err == io.EOF {
+			// Incomplete lines are completed with the next read
+			e.pending += line
+			return events, restarted, nil
+		} else if err != nil {
+			return nil, false, err
 		}
 
-		err = updateSyncMap(syncMap, jsonMap)
+		line = e.pending + line
+		e.pending = ""
+
+		event := &sync.Event{}
+		err = json.Unmarshal([]byte(line), event)
 		if err != nil {
-			log.Fatal(err)
+			return nil, false, fmt.Errorf("Json object is invalid %s: %v", line, err)
 		}
+
+		events = append(events, event)
+	}
+}
+
+func getSortedStatus(syncMap map[string]*syncStatus) []*syncStatus {
+	identifiers := make([]string, 0, len(syncMap))
+	for identifier := range syncMap {
+		identifiers = append(identifiers, identifier)
+	}
+
+	sort.Strings(identifiers)
+
+	statuses := make([]*syncStatus, 0, len(identifiers))
+	for _, identifier := range identifiers {
+		statuses = append(statuses, syncMap[identifier])
 	}
 
+	return statuses
+}
+
+func printSyncStatus(syncMap map[string]*syncStatus) {
 	if len(syncMap) == 0 {
 		log.Info("No sync activity found. Did you run `devspace dev`?")
 		return
@@ -112,46 +245,43 @@ func (cmd *syncCmd) RunStatusSync(cobraCmd *cobra.Command, args []string) {
 		"Container",
 		"Latest Activity",
 		"Total Changes",
+		"Total Size",
 	}
 
 	values := make([][]string, 0, len(syncMap))
 
-	for _, status := range syncMap {
+	for _, status := range getSortedStatus(syncMap) {
 		latestActivity := status.LastActivity
 
 		if status.Error != "" {
 			latestActivity = status.Error
 		}
 
-		parsedTime, _ := time.Parse(time.RFC3339, status.LastActivityTime)
-		if parsedTime.Unix() == 0 {
-			parsedTime = time.Now()
-		}
-
-		latestActivity += " (" + intToTimeString(int(time.Now().Unix()-parsedTime.Unix())) + " ago)"
+		latestActivity += " (" + intToTimeString(int(time.Now().Unix()-status.LastActivityTime.Unix())) + " ago)"
 
-		syncStatus := status.Status
-		if syncStatus == "" {
-			syncStatus = "Active"
+		pod := status.Pod
+		if len(pod) > 15 {
+			pod = pod[:15] + "..."
 		}
 
-		if len(status.Pod) > 15 {
-			status.Pod = status.Pod[:15] + "..."
+		local := status.Local
+		if len(local) > 20 {
+			local = "..." + local[len(local)-20:]
 		}
-		if len(status.Local) > 20 {
-			status.Local = "..." + status.Local[len(status.Local)-20:len(status.Local)]
-		}
-		if len(status.Container) > 20 {
-			status.Container = "..." + status.Container[len(status.Container)-20:len(status.Container)]
+
+		container := status.Container
+		if len(container) > 20 {
+			container = "..." + container[len(container)-20:]
 		}
 
 		values = append(values, []string{
-			syncStatus,
-			status.Pod,
-			status.Local,
-			status.Container,
+			status.Status,
+			pod,
+			local,
+			container,
 			latestActivity,
 			strconv.Itoa(status.TotalChanges),
+			formatBytes(status.TotalBytes),
 		})
 	}
 
@@ -197,49 +327,61 @@ func intToTimeString(timeDifference int) string {
 	return "0s"
 }
 
-func isSyncJSONMapInvalid(jsonMap map[string]string) bool {
-	return jsonMap["container"] == "" || jsonMap["local"] == "" || jsonMap["pod"] == "" || jsonMap["level"] == "" || jsonMap["time"] == "" || jsonMap["msg"] == ""
-}
+func formatBytes(bytes int64) string {
+	units := []string{"B", "KB", "MB", "GB"}
 
-func updateSyncMap(syncMap map[string]*syncStatus, jsonMap map[string]string) error {
-	pod := jsonMap["pod"]
-	local := jsonMap["local"]
-	container := jsonMap["container"]
-	message := jsonMap["msg"]
-	level := jsonMap["level"]
-	time := jsonMap["time"]
+	size := float64(bytes)
+	unit := 0
+	for size >= 1024 && unit < len(units)-1 {
+		size /= 1024
+		unit++
+	}
 
-	identifier := pod + ":" + local + ":" + container
+	if unit == 0 {
+		return strconv.FormatInt(bytes, 10) + " B"
+	}
+
+	return strconv.FormatFloat(math.Round(size*10)/10, 'f', -1, 64) + " " + units[unit]
+}
+
+func updateSyncMap(syncMap map[string]*syncStatus, event *sync.Event) {
+	identifier := event.Pod + ":" + event.Local + ":" + event.Container
 
 	if syncMap[identifier] == nil {
 		syncMap[identifier] = &syncStatus{
-			Pod:       pod,
-			Container: container,
-			Local:     local,
+			Status:    "Active",
+			Pod:       event.Pod,
+			Container: event.Container,
+			Local:     event.Local,
 		}
 	}
 
-	if level == "error" {
-		syncMap[identifier].Status = "Error"
-		syncMap[identifier].Error = message
-		syncMap[identifier].LastActivityTime = time
-	} else if matches := downstreamChanges.FindStringSubmatch(message); len(matches) == 2 {
-		syncMap[identifier].LastActivity = "Downloaded " + matches[1] + " changes"
-		syncMap[identifier].LastActivityTime = time
-
-		changes, _ := strconv.Atoi(matches[1])
-		syncMap[identifier].TotalChanges += changes
-	} else if matches := upstreamChanges.FindStringSubmatch(message); len(matches) == 2 {
-		syncMap[identifier].LastActivity = "Uploaded " + matches[1] + " changes"
-		syncMap[identifier].LastActivityTime = time
+	status := syncMap[identifier]
+	status.LastActivityTime = event.Time
+
+	switch event.Type {
+	case sync.EventInitialSyncStarted:
+		status.Status = "Initial Sync"
+		status.LastActivity = "Initial sync started"
+	case sync.EventInitialSyncDone:
+		status.Status = "Active"
+		status.LastActivity = "Initial sync completed"
+	case sync.EventUploaded:
+		status.LastActivity = "Uploaded " + strconv.Itoa(event.Changes) + " changes"
+		status.TotalChanges += event.Changes
+		status.TotalBytes += event.Bytes
+	case sync.EventDownloaded:
+		status.LastActivity = "Downloaded " + strconv.Itoa(event.Changes) + " changes"
+		status.TotalChanges += event.Changes
+		status.TotalBytes += event.Bytes
+	case sync.EventError:
+		status.Status = "Error"
+		status.Error = event.Error
+	case sync.EventStopped:
+		if status.Status != "Error" {
+			status.Status = "Stopped"
+		}
 
-		changes, _ := strconv.Atoi(matches[1])
-		syncMap[identifier].TotalChanges += changes
-	} else if syncStopped.MatchString(message) {
-		syncMap[identifier].Status = "Stopped"
-		syncMap[identifier].LastActivity = "Sync stopped"
-		syncMap[identifier].LastActivityTime = time
+		status.LastActivity = "Sync stopped"
 	}
-
-	return nil
 }
@@ -1,11 +1,14 @@
 package cmd
 
 import (
+	"os"
+
 	"github.com/devspace-cloud/devspace/pkg/devspace/config/configutil"
 	latest "github.com/devspace-cloud/devspace/pkg/devspace/config/versions/latest"
 	"github.com/devspace-cloud/devspace/pkg/devspace/kubectl"
 	"github.com/devspace-cloud/devspace/pkg/devspace/services"
 	"github.com/devspace-cloud/devspace/pkg/devspace/services/targetselector"
+	"github.com/devspace-cloud/devspace/pkg/devspace/sync"
 	"github.com/devspace-cloud/devspace/pkg/util/log"
 	"github.com/spf13/cobra"
 )
@@ -21,6 +24,12 @@ type SyncCmd struct {
 
 	Exclude       []string
 	ContainerPath string
+
+	Once         bool
+	DryRun       bool
+	UploadOnly   bool
+	DownloadOnly bool
+	Delete       bool
 }
 
 // NewSyncCmd creates a new init command
@@ -41,6 +50,8 @@ devspace sync
 devspace sync --exclude=node_modules --exclude=test
 devspace sync --pod=my-pod --container=my-container
 devspace sync --container-path=/my-path
+devspace sync --once --upload-only
+devspace sync --dry-run
 #######################################################`,
 		Run: cmd.Run,
 	}
@@ -55,6 +66,12 @@ devspace sync --container-path=/my-path
 	syncCmd.Flags().StringSliceVarP(&cmd.Exclude, "exclude", "e", []string{}, "Exclude directory from sync")
 	syncCmd.Flags().StringVar(&cmd.ContainerPath, "container-path", "", "Container path to use (Default is working directory)")
 
+	syncCmd.Flags().BoolVar(&cmd.Once, "once", false, "Synchronize a single time and exit afterwards")
+	syncCmd.Flags().BoolVar(&cmd.DryRun, "dry-run", false, "Print the files that would be synchronized without changing them (exit code 2 if there are differences)")
+	syncCmd.Flags().BoolVar(&cmd.UploadOnly, "upload-only", false, "Only upload local changes to the container")
+	syncCmd.Flags().BoolVar(&cmd.DownloadOnly, "download-only", false, "Only download changes from the container")
+	syncCmd.Flags().BoolVar(&cmd.Delete, "delete", false, "Delete files that only exist on the target side (requires --once or --dry-run and --upload-only or --download-only)")
+
 	return syncCmd
 }
 
@@ -92,9 +109,60 @@ func (cmd *SyncCmd) Run(cobraCmd *cobra.Command, args []string) {
 		params.Pick = &cmd.Pick
 	}
 
-	// Start terminal
-	err = services.StartSyncFromCmd(config, kubectl, params, cmd.ContainerPath, cmd.Exclude, log.GetInstance())
+	if cmd.UploadOnly && cmd.DownloadOnly {
+		log.Fatal("Flags --upload-only and --download-only can't be used together")
+	}
+	if cmd.Delete && ((cmd.Once == false && cmd.DryRun == false) || (cmd.UploadOnly == false && cmd.DownloadOnly == false)) {
+		log.Fatal("Flag --delete requires --once or --dry-run and --upload-only or --download-only")
+	}
+
+	options := &services.SyncCmdOptions{
+		ContainerPath: cmd.ContainerPath,
+		Exclude:       cmd.Exclude,
+		UploadOnly:    cmd.UploadOnly,
+		DownloadOnly:  cmd.DownloadOnly,
+		Delete:        cmd.Delete,
+	}
+
+	if cmd.Once || cmd.DryRun {
+		plan, err := services.RunSyncOnceFromCmd(config, kubectl, params, options, cmd.DryRun, log.GetInstance())
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		printSyncPlan(plan, cmd.DryRun)
+		if cmd.DryRun && plan.Empty() == false {
+			os.Exit(2)
+		}
+
+		return
+	}
+
+	// Start sync
+	err = services.StartSyncFromCmd(config, kubectl, params, options, log.GetInstance())
 	if err != nil {
 		log.Fatal(err)
 	}
 }
+
+func printSyncPlan(plan *sync.Plan, dryRun bool) {
+	if plan.Empty() {
+		log.Done("Local path and container path are in sync")
+		return
+	}
+
+	values := [][]string{}
+	for _, change := range plan.Upload {
+		values = append(values, []string{"container", string(change.Type), change.Path})
+	}
+	for _, change := range plan.Download {
+		values = append(values, []string{"local", string(change.Type), change.Path})
+	}
+
+	log.PrintTable([]string{"Side", "Change", "Path"}, values)
+	if dryRun {
+		log.Infof("%d file(s) would be changed in the container and %d file(s) locally", len(plan.Upload), len(plan.Download))
+	} else {
+		log.Donef("Changed %d file(s) in the container and %d file(s) locally", len(plan.Upload), len(plan.Download))
+	}
+}
@@ -0,0 +1,58 @@
+package update
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/configutil"
+	"github.com/devspace-cloud/devspace/pkg/util/log"
+
+	"github.com/spf13/cobra"
+)
+
+// importsCmd holds the cmd flags
+type importsCmd struct{}
+
+// newImportsCmd creates a new command
+func newImportsCmd() *cobra.Command {
+	cmd := &importsCmd{}
+
+	importsCmd := &cobra.Command{
+		Use:   "imports",
+		Short: "Updates the git repositories of the imports defined in the devspace.yaml",
+		Long: `
+#######################################################
+############### devspace update imports ###############
+#######################################################
+Clears the cached git repositories of the imports
+defined in the devspace.yaml and pulls them again
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+		Run:  cmd.RunImports,
+	}
+
+	return importsCmd
+}
+
+// RunImports executes the functionality "devspace update imports"
+func (cmd *importsCmd) RunImports(cobraCmd *cobra.Command, args []string) {
+	// Set config root
+	configExists, err := configutil.SetDevSpaceRoot()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if !configExists {
+		log.Fatal("Couldn't find a DevSpace configuration. Please run `devspace init`")
+	}
+
+	err = os.RemoveAll(filepath.FromSlash(configutil.ImportsFolder))
+	if err != nil {
+		log.Fatalf("Error clearing import cache: %v", err)
+	}
+
+	// Loading the config pulls all imports again
+	configutil.GetConfig()
+
+	log.Donef("Successfully updated all imports")
+}
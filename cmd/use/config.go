@@ -1,6 +1,8 @@
 package use
 
 import (
+	"strings"
+
 	"github.com/devspace-cloud/devspace/pkg/devspace/config/configs"
 	"github.com/devspace-cloud/devspace/pkg/devspace/config/configutil"
 	"github.com/devspace-cloud/devspace/pkg/devspace/config/generated"
@@ -8,12 +10,14 @@ import (
 	"github.com/spf13/cobra"
 )
 
-type configCmd struct{}
+type configCmd struct {
+	Profiles []string
+}
 
 func newConfigCmd() *cobra.Command {
 	cmd := &configCmd{}
 
-	return &cobra.Command{
+	configCmd := &cobra.Command{
 		Use:   "config",
 		Short: "Use a specific DevSpace configuration",
 		Long: `
@@ -23,6 +27,9 @@ func newConfigCmd() *cobra.Command {
 Use a specific DevSpace configuration that is defined
 in devspace-configs.yaml
 
+Profiles of the config are applied in the given order:
+devspace use config myconfig --profile staging,debug
+
 Example:
 devspace use config myconfig
 #######################################################
@@ -30,10 +37,14 @@ devspace use config myconfig
 		Args: cobra.ExactArgs(1),
 		Run:  cmd.RunUseConfig,
 	}
+
+	configCmd.Flags().StringSliceVar(&cmd.Profiles, "profile", []string{}, "Comma separated list of profiles to apply to the config")
+
+	return configCmd
 }
 
 // RunUseConfig executes the "devspace use config command" logic
-func (*configCmd) RunUseConfig(cobraCmd *cobra.Command, args []string) {
+func (cmd *configCmd) RunUseConfig(cobraCmd *cobra.Command, args []string) {
 	// Set config root
 	configExists, err := configutil.SetDevSpaceRoot()
 	if err != nil {
@@ -50,10 +61,23 @@ func (*configCmd) RunUseConfig(cobraCmd *cobra.Command, args []string) {
 	}
 
 	// Check if config exists
-	if _, ok := configs[args[0]]; ok == false {
+	configDefinition, ok := configs[args[0]]
+	if ok == false {
 		log.Fatalf("Config '%s' does not exist in %s", args[0], configutil.DefaultConfigsPath)
 	}
 
+	// Check if profiles exist
+	err = configutil.ValidateProfiles(configDefinition)
+	if err != nil {
+		log.Fatalf("Error in config '%s': %v", args[0], err)
+	}
+	for _, profile := range cmd.Profiles {
+		_, err = configutil.GetProfile(configDefinition, profile)
+		if err != nil {
+			log.Fatalf("%v in config '%s'", err, args[0])
+		}
+	}
+
 	// Load generated config
 	generatedConfig, err := generated.LoadConfig()
 	if err != nil {
@@ -62,6 +86,7 @@ func (*configCmd) RunUseConfig(cobraCmd *cobra.Command, args []string) {
 
 	// Exchange active config
 	generatedConfig.ActiveConfig = args[0]
+	generatedConfig.ActiveProfiles = cmd.Profiles
 
 	// Save generated config
 	err = generated.SaveConfig(generatedConfig)
@@ -69,5 +94,9 @@ func (*configCmd) RunUseConfig(cobraCmd *cobra.Command, args []string) {
 		log.Fatalf("Error saving generated config: %v", err)
 	}
 
-	log.Infof("Successfully switched to config '%s'", args[0])
+	if len(cmd.Profiles) > 0 {
+		log.Infof("Successfully switched to config '%s' with profiles %s", args[0], strings.Join(cmd.Profiles, ", "))
+	} else {
+		log.Infof("Successfully switched to config '%s'", args[0])
+	}
 }
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/configutil"
+	"github.com/devspace-cloud/devspace/pkg/util/log"
+	"github.com/spf13/cobra"
+)
+
+// ValidateCmd is a struct that defines a command call for "validate"
+type ValidateCmd struct{}
+
+// NewValidateCmd creates a new validate command
+func NewValidateCmd() *cobra.Command {
+	cmd := &ValidateCmd{}
+
+	validateCmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validates the DevSpace configuration",
+		Long: `
+#######################################################
+################## devspace validate ##################
+#######################################################
+Validates devspace.yaml or all configs and overrides in
+devspace-configs.yaml against the json schema of their
+config version and reports unknown keys, wrong types
+and missing required fields
+#######################################################`,
+		Args: cobra.NoArgs,
+		Run:  cmd.Run,
+	}
+
+	return validateCmd
+}
+
+// Run executes the command logic
+func (cmd *ValidateCmd) Run(cobraCmd *cobra.Command, args []string) {
+	// Set config root
+	configExists, err := configutil.SetDevSpaceRoot()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if !configExists {
+		log.Fatal("Couldn't find a DevSpace configuration. Please run `devspace init`")
+	}
+
+	schemaErrors, err := configutil.ValidateSchema(".")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(schemaErrors) > 0 {
+		for _, schemaError := range schemaErrors {
+			log.Error(schemaError.Error())
+		}
+
+		log.Fatalf("Found %d errors in the DevSpace configuration", len(schemaErrors))
+	}
+
+	log.Done("The DevSpace configuration is valid")
+}
@@ -61,6 +61,7 @@ require (
 	github.com/juju/ratelimit v1.0.1
 	github.com/k0kubun/go-ansi v0.0.0-20180517002512-3bf9e2903213
 	github.com/kevinburke/ssh_config v0.0.0-20180830205328-81db2a75821e // indirect
+	github.com/klauspost/compress v1.8.2
 	github.com/lxn/win v0.0.0-20181015143721-a7f87360b10e // indirect
 	github.com/machinebox/graphql v0.2.2
 	github.com/magiconair/properties v1.8.0 // indirect
@@ -217,13 +217,13 @@ func (b *Builder) BuildImage(contextPath, dockerfilePath string, entrypoint *[]*
 		log.StartWait("Uploading files to build container")
 
 		// Copy complete context
-		err = sync.CopyToContainer(b.kubectl, buildPod, &buildPod.Spec.InitContainers[0], contextPath, kanikoContextPath, ignoreRules)
+		err = sync.CopyToContainer(b.helper.Config, b.kubectl, buildPod, &buildPod.Spec.InitContainers[0], contextPath, kanikoContextPath, ignoreRules)
 		if err != nil {
 			return fmt.Errorf("Error uploading files to container: %v", err)
 		}
 
 		// Copy dockerfile
-		err = sync.CopyToContainer(b.kubectl, buildPod, &buildPod.Spec.InitContainers[0], dockerfilePath, kanikoContextPath, ignoreRules)
+		err = sync.CopyToContainer(b.helper.Config, b.kubectl, buildPod, &buildPod.Spec.InitContainers[0], dockerfilePath, kanikoContextPath, ignoreRules)
 		if err != nil {
 			return fmt.Errorf("Error uploading files to container: %v", err)
 		}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/devspace-cloud/devspace/pkg/devspace/kubectl"
+	"github.com/devspace-cloud/devspace/pkg/util/encryption"
 	"github.com/devspace-cloud/devspace/pkg/util/hash"
 	"github.com/devspace-cloud/devspace/pkg/util/log"
 	"github.com/devspace-cloud/devspace/pkg/util/ptr"
@@ -117,7 +118,7 @@ func (p *Provider) ConnectCluster(options *ConnectClusterOptions) error {
 		return errors.Wrap(err, "get service account credentials")
 	}
 
-	encryptedToken, err := EncryptAES([]byte(options.Key), token)
+	encryptedToken, err := encryption.EncryptAES([]byte(options.Key), token)
 	if err != nil {
 		return errors.Wrap(err, "encrypt token")
 	}
@@ -557,7 +558,7 @@ func (p *Provider) ResetKey(clusterName string) error {
 		return errors.Wrap(err, "get service account credentials")
 	}
 
-	encryptedToken, err := EncryptAES([]byte(key), token)
+	encryptedToken, err := encryption.EncryptAES([]byte(key), token)
 	if err != nil {
 		return errors.Wrap(err, "encrypt token")
 	}
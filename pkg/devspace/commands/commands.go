@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/versions/latest"
+	"github.com/devspace-cloud/devspace/pkg/devspace/kubectl"
+	"github.com/devspace-cloud/devspace/pkg/devspace/services/targetselector"
+	"github.com/devspace-cloud/devspace/pkg/util/log"
+	"github.com/mgutz/ansi"
+	kubectlExec "k8s.io/client-go/util/exec"
+)
+
+// ExitCodeError is returned if a command exited with a non zero exit code
+type ExitCodeError struct {
+	Name     string
+	ExitCode int
+}
+
+// Error implements the error interface
+func (e *ExitCodeError) Error() string {
+	return fmt.Sprintf("Command %s exited with code %d", e.Name, e.ExitCode)
+}
+
+// GetCommand returns the command with the given name
+func GetCommand(config *latest.Config, name string) (*latest.CommandConfig, error) {
+	if config.Commands != nil {
+		for _, command := range *config.Commands {
+			if command.Name != nil && *command.Name == name {
+				return command, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("Couldn't find command %s, run `devspace list commands` to see all available commands", name)
+}
+
+// ExecuteCommand executes the command with the given name and appends the args to it. Commands with a selector
+// are executed in the selected container, all other commands are executed locally
+func ExecuteCommand(config *latest.Config, name string, args []string, log log.Logger) error {
+	command, err := GetCommand(config, name)
+	if err != nil {
+		return err
+	}
+
+	shellCommand := getShellCommand(*command.Command, args)
+	if command.Selector == nil {
+		return executeLocal(name, shellCommand, os.Stdout, os.Stderr, os.Stdin)
+	}
+
+	return executeInContainer(config, command, shellCommand, log)
+}
+
+// getShellCommand appends the quoted args to the command
+func getShellCommand(command string, args []string) string {
+	for _, arg := range args {
+		command += " '" + strings.Replace(arg, "'", "'\\''", -1) + "'"
+	}
+
+	return command
+}
+
+func executeLocal(name, shellCommand string, stdout io.Writer, stderr io.Writer, stdin io.Reader) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", shellCommand)
+	} else {
+		cmd = exec.Command("sh", "-c", shellCommand)
+	}
+
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.Stdin = stdin
+
+	err := cmd.Run()
+	if exitError, ok := err.(*exec.ExitError); ok {
+		if status, ok := exitError.Sys().(interface{ ExitStatus() int }); ok {
+			return &ExitCodeError{Name: name, ExitCode: status.ExitStatus()}
+		}
+	}
+
+	return err
+}
+
+func executeInContainer(config *latest.Config, command *latest.CommandConfig, shellCommand string, log log.Logger) error {
+	client, err := kubectl.NewClient(config)
+	if err != nil {
+		return fmt.Errorf("Unable to create new kubectl client: %v", err)
+	}
+
+	targetSelector, err := targetselector.NewTargetSelector(config, &targetselector.SelectorParameter{
+		ConfigParameter: targetselector.ConfigParameter{
+			Selector:      command.Selector,
+			ContainerName: command.ContainerName,
+		},
+	}, true)
+	if err != nil {
+		return err
+	}
+
+	pod, container, err := targetSelector.GetContainer(client)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Executing command %s in pod:container %s:%s", ansi.Color(*command.Name, "white+b"), ansi.Color(pod.Name, "white+b"), ansi.Color(container.Name, "white+b"))
+
+	err = kubectl.ExecStream(config, client, pod, container.Name, []string{"sh", "-c", shellCommand}, true, os.Stdin, os.Stdout, os.Stderr)
+	if exitError, ok := err.(kubectlExec.CodeExitError); ok {
+		return &ExitCodeError{Name: *command.Name, ExitCode: exitError.ExitStatus()}
+	}
+
+	return err
+}
@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"bytes"
+	"runtime"
+	"testing"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/versions/latest"
+	"github.com/devspace-cloud/devspace/pkg/util/ptr"
+)
+
+func TestExecuteLocal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping test on windows")
+	}
+
+	config := &latest.Config{
+		Commands: &[]*latest.CommandConfig{
+			{
+				Name:    ptr.String("greet"),
+				Command: ptr.String("echo hello"),
+			},
+		},
+	}
+
+	command, err := GetCommand(config, "greet")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = GetCommand(config, "missing")
+	if err == nil {
+		t.Fatal("Expected error for unknown command")
+	}
+
+	stdout := &bytes.Buffer{}
+	err = executeLocal("greet", getShellCommand(*command.Command, []string{"world", "it's me"}), stdout, stdout, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stdout.String() != "hello world it's me\n" {
+		t.Fatalf("Unexpected output %q", stdout.String())
+	}
+
+	err = executeLocal("fail", "exit 3", stdout, stdout, nil)
+	if exitCodeError, ok := err.(*ExitCodeError); ok == false || exitCodeError.ExitCode != 3 {
+		t.Fatalf("Expected exit code 3, got %v", err)
+	}
+}
@@ -8,6 +8,22 @@ type ConfigDefinition struct {
 	Config    *ConfigWrapper    `yaml:"config,omitempty"`
 	Vars      *VarsWrapper      `yaml:"vars,omitempty"`
 	Overrides *[]*ConfigWrapper `yaml:"overrides,omitempty"`
+	Profiles  *[]*Profile       `yaml:"profiles,omitempty"`
+}
+
+// Profile is a named list of patches that can be activated with devspace use config --profile
+type Profile struct {
+	Name    *string         `yaml:"name"`
+	Patches *[]*PatchConfig `yaml:"patches,omitempty"`
+}
+
+// PatchConfig describes a single json patch operation (RFC 6902). List items within path and from
+// can be selected by index or by name (e.g. /deployments/name=backend/helm)
+type PatchConfig struct {
+	Operation *string     `yaml:"op"`
+	Path      *string     `yaml:"path"`
+	From      *string     `yaml:"from,omitempty"`
+	Value     interface{} `yaml:"value,omitempty"`
 }
 
 // ConfigWrapper specifies if the config is infile or should be loaded from a path
@@ -25,9 +41,25 @@ type VarsWrapper struct {
 // Variable describes the var definition
 type Variable struct {
 	Name              *string   `yaml:"name"`
+	Type              *string   `yaml:"type,omitempty"`
 	Options           *[]string `yaml:"options,omitempty"`
 	Default           *string   `yaml:"default,omitempty"`
 	Question          *string   `yaml:"question,omitempty"`
 	ValidationPattern *string   `yaml:"validationPattern,omitempty"`
 	ValidationMessage *string   `yaml:"validationMessage,omitempty"`
+
+	Source *VariableSource `yaml:"source,omitempty"`
+	Cache  *string         `yaml:"cache,omitempty"`
+
+	// Secret values are stored encrypted outside of the project and never printed
+	Secret *bool `yaml:"secret,omitempty"`
+}
+
+// VariableSource defines where the value of a variable is loaded from instead of asking the user.
+// Exactly one of command, file and envFile has to be specified
+type VariableSource struct {
+	Command *string `yaml:"command,omitempty"`
+	File    *string `yaml:"file,omitempty"`
+	EnvFile *string `yaml:"envFile,omitempty"`
+	Key     *string `yaml:"key,omitempty"`
 }
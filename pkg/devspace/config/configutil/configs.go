@@ -28,7 +28,7 @@ func loadVarsFromWrapper(basePath string, varsWrapper *configs.VarsWrapper) ([]*
 			return nil, fmt.Errorf("Error loading %s: %v", *varsWrapper.Path, err)
 		}
 
-		err = yaml.UnmarshalStrict(yamlFileContent, returnVars)
+		err = yaml.UnmarshalStrict(yamlFileContent, &returnVars)
 		if err != nil {
 			return nil, fmt.Errorf("Error parsing %s: %v", *varsWrapper.Path, err)
 		}
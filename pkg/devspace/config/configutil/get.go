@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -112,7 +113,7 @@ func GetConfig() *latest.Config {
 	return config
 }
 
-func loadBaseConfigFromPath(basePath string, loadConfig string, loadOverwrites bool, generatedConfig *generated.Config, log log.Logger) (*latest.Config, *configs.ConfigDefinition, error) {
+func loadBaseConfigFromPath(basePath string, loadConfig string, loadOverwrites bool, generatedConfig *generated.Config, recorder *stageRecorder, log log.Logger) (*latest.Config, *configs.ConfigDefinition, error) {
 	var (
 		config           = latest.New().(*latest.Config)
 		configRaw        = latest.New().(*latest.Config)
@@ -122,6 +123,9 @@ func loadBaseConfigFromPath(basePath string, loadConfig string, loadOverwrites b
 		varsPath         = filepath.Join(basePath, DefaultVarsPath)
 	)
 
+	// Variables are defined per config, dependencies must not override them
+	defer useVarScope(basePath, loadConfig)()
+
 	// Check if configs.yaml exists
 	_, err := os.Stat(configsPath)
 	if err == nil {
@@ -159,7 +163,7 @@ func loadBaseConfigFromPath(basePath string, loadConfig string, loadOverwrites b
 				return nil, nil, fmt.Errorf("Error loading vars: %v", err)
 			}
 
-			err = askQuestions(generatedConfig.GetActive(), vars)
+			err = resolveDefinedVars(basePath, loadConfig, generatedConfig.GetActive(), vars)
 			if err != nil {
 				return nil, nil, fmt.Errorf("Error filling vars: %v", err)
 			}
@@ -179,13 +183,13 @@ func loadBaseConfigFromPath(basePath string, loadConfig string, loadOverwrites b
 				return nil, nil, fmt.Errorf("Error loading %s: %v", varsPath, err)
 			}
 
-			err = yaml.UnmarshalStrict(yamlFileContent, vars)
+			err = yaml.UnmarshalStrict(yamlFileContent, &vars)
 			if err != nil {
 				return nil, nil, fmt.Errorf("Error parsing %s: %v", varsPath, err)
 			}
 
 			// Ask questions
-			err = askQuestions(generatedConfig.GetActive(), vars)
+			err = resolveDefinedVars(basePath, loadConfig, generatedConfig.GetActive(), vars)
 			if err != nil {
 				return nil, nil, fmt.Errorf("Error filling vars: %v", err)
 			}
@@ -197,7 +201,20 @@ func loadBaseConfigFromPath(basePath string, loadConfig string, loadOverwrites b
 		}
 	}
 
+	// Merge imported partials before the config itself. We don't load imports for the base config, because
+	// it might be saved afterwards
+	if loadOverwrites && configRaw.Imports != nil {
+		err = mergeImports(basePath, &config, *configRaw.Imports, recorder, log)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	Merge(&config, deepCopy(configRaw))
+	err = recorder.record("base", config)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	// Check if we should load overrides
 	if loadOverwrites {
@@ -210,13 +227,38 @@ func loadBaseConfigFromPath(basePath string, loadConfig string, loadOverwrites b
 					}
 
 					Merge(&config, overwriteConfig)
+					err = recorder.record(fmt.Sprintf("overrides[%d]", index), config)
+					if err != nil {
+						return nil, nil, err
+					}
 				}
 
 				log.Infof("Loaded config %s from %s with %d overrides", LoadedConfig, DefaultConfigsPath, len(*configDefinition.Overrides))
 			} else {
 				log.Infof("Loaded config %s from %s", LoadedConfig, DefaultConfigsPath)
 			}
+
+			activeProfiles := getActiveProfiles(basePath, loadConfig, generatedConfig)
+			if len(activeProfiles) > 0 {
+				for _, profile := range activeProfiles {
+					config, err = applyProfiles(config, configDefinition, []string{profile})
+					if err != nil {
+						return nil, nil, fmt.Errorf("Error applying profiles: %v", err)
+					}
+
+					err = recorder.record("profile "+profile, config)
+					if err != nil {
+						return nil, nil, err
+					}
+				}
+
+				log.Infof("Applied profiles %s", strings.Join(activeProfiles, ", "))
+			}
 		} else {
+			if activeProfiles := getActiveProfiles(basePath, loadConfig, generatedConfig); len(activeProfiles) > 0 {
+				log.Warnf("Ignoring active profiles %s, because profiles can only be defined in %s", strings.Join(activeProfiles, ", "), DefaultConfigsPath)
+			}
+
 			log.Infof("Loaded config from %s", DefaultConfigPath)
 		}
 
@@ -229,9 +271,18 @@ func loadBaseConfigFromPath(basePath string, loadConfig string, loadOverwrites b
 					return nil, nil, fmt.Errorf("No space configured!\n\nPlease run: \n- `%s` to create a new space\n- `%s` to use an existing space\n- `%s` to list existing spaces", ansi.Color("devspace create space [NAME]", "white+b"), ansi.Color("devspace use space [NAME]", "white+b"), ansi.Color("devspace list spaces", "white+b"))
 				}
 
-				config.Cluster = &latest.Cluster{
+				cluster := &latest.Cluster{
 					KubeContext: &generatedConfig.CloudSpace.KubeContext,
 				}
+
+				// Keep the guardrails of the config for the space
+				if config.Cluster != nil {
+					cluster.AllowedContexts = config.Cluster.AllowedContexts
+					cluster.AllowedNamespaces = config.Cluster.AllowedNamespaces
+					cluster.Protected = config.Cluster.Protected
+				}
+
+				config.Cluster = cluster
 			}
 		}
 	} else {
@@ -245,9 +296,29 @@ func loadBaseConfigFromPath(basePath string, loadConfig string, loadOverwrites b
 	return config, configDefinition, nil
 }
 
+// getActiveProfiles returns the profiles that were activated with devspace use config. They only belong to
+// the active config of the project and are not applied to other configs or the configs of dependencies
+func getActiveProfiles(basePath, loadConfig string, generatedConfig *generated.Config) []string {
+	if loadConfig != generatedConfig.ActiveConfig {
+		return nil
+	}
+
+	absPath, err := filepath.Abs(basePath)
+	if err != nil {
+		return nil
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil || absPath != cwd {
+		return nil
+	}
+
+	return generatedConfig.ActiveProfiles
+}
+
 // GetConfigFromPath loads the config from a given base path
 func GetConfigFromPath(basePath string, loadConfig string, loadOverrides bool, generatedConfig *generated.Config, log log.Logger) (*latest.Config, error) {
-	config, _, err := loadBaseConfigFromPath(basePath, loadConfig, loadOverrides, generatedConfig, log)
+	config, _, err := loadBaseConfigFromPath(basePath, loadConfig, loadOverrides, generatedConfig, nil, log)
 	if err != nil {
 		return nil, err
 	}
@@ -276,9 +347,10 @@ func GetConfigWithoutDefaults(loadOverwrites bool) *latest.Config {
 
 		// Get config to load
 		LoadedConfig = generatedConfig.ActiveConfig
+		loadedVarScope = getVarScope(".", LoadedConfig)
 
 		// Load base config
-		config, configDefinition, err = loadBaseConfigFromPath(".", LoadedConfig, loadOverwrites, generatedConfig, log.GetInstance())
+		config, configDefinition, err = loadBaseConfigFromPath(".", LoadedConfig, loadOverwrites, generatedConfig, nil, log.GetInstance())
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -320,11 +392,11 @@ func validate(config *latest.Config) error {
 
 		if config.Dev.Ports != nil {
 			for index, port := range *config.Dev.Ports {
-				if port.Selector == nil && port.LabelSelector == nil {
-					return fmt.Errorf("Error in config: selector and label selector are nil in port config at index %d", index)
+				if port.Selector == nil && port.LabelSelector == nil && port.Service == nil {
+					return fmt.Errorf("Error in config: selector, label selector and service are nil in port config at index %d", index)
 				}
-				if port.PortMappings == nil {
-					return fmt.Errorf("Error in config: portMappings is empty in port config at index %d", index)
+				if port.PortMappings == nil && port.ReverseForward == nil && (port.ForwardContainerPorts == nil || *port.ForwardContainerPorts == false) {
+					return fmt.Errorf("Error in config: forward and reverseForward are empty in port config at index %d", index)
 				}
 			}
 		}
@@ -334,6 +406,28 @@ func validate(config *latest.Config) error {
 				if sync.Selector == nil && sync.LabelSelector == nil {
 					return fmt.Errorf("Error in config: selector and label selector are nil in sync config at index %d", index)
 				}
+				if sync.FileMode != nil {
+					if _, err := strconv.ParseUint(*sync.FileMode, 8, 32); err != nil {
+						return fmt.Errorf("Error in config: fileMode %s is not an octal file mode (e.g. 0644) in sync config at index %d", *sync.FileMode, index)
+					}
+				}
+				if sync.DirMode != nil {
+					if _, err := strconv.ParseUint(*sync.DirMode, 8, 32); err != nil {
+						return fmt.Errorf("Error in config: dirMode %s is not an octal file mode (e.g. 0755) in sync config at index %d", *sync.DirMode, index)
+					}
+				}
+				if sync.OnUpload != nil {
+					for onUploadIndex, onUpload := range *sync.OnUpload {
+						if onUpload == nil || onUpload.Command == nil || len(*onUpload.Command) == 0 {
+							return fmt.Errorf("Error in config: command is required in onUpload hook at index %d of sync config at index %d", onUploadIndex, index)
+						}
+						for argIndex, arg := range *onUpload.Command {
+							if arg == nil {
+								return fmt.Errorf("Error in config: command contains an empty element at index %d in onUpload hook at index %d of sync config at index %d", argIndex, onUploadIndex, index)
+							}
+						}
+					}
+				}
 			}
 		}
 
@@ -354,6 +448,23 @@ func validate(config *latest.Config) error {
 		}
 	}
 
+	if config.Commands != nil {
+		commandNames := map[string]bool{}
+		for index, commandConfig := range *config.Commands {
+			if commandConfig.Name == nil {
+				return fmt.Errorf("commands[%d].name is required", index)
+			}
+			if commandConfig.Command == nil {
+				return fmt.Errorf("commands[%d].command is required", index)
+			}
+			if commandNames[*commandConfig.Name] {
+				return fmt.Errorf("commands[%d].name %s is used by multiple commands", index, *commandConfig.Name)
+			}
+
+			commandNames[*commandConfig.Name] = true
+		}
+	}
+
 	if config.Images != nil {
 		for imageConfigName, imageConf := range *config.Images {
 			if imageConf.Build != nil && imageConf.Build.Custom != nil && imageConf.Build.Custom.Command == nil {
@@ -382,24 +493,6 @@ func validate(config *latest.Config) error {
 	return nil
 }
 
-func askQuestions(cache *generated.CacheConfig, vars []*configs.Variable) error {
-	for idx, variable := range vars {
-		if variable.Name == nil {
-			return fmt.Errorf("Name required for variable with index %d", idx)
-		}
-
-		if os.Getenv(VarEnvPrefix+strings.ToUpper(*variable.Name)) != "" {
-			continue
-		} else if _, ok := cache.Vars[*variable.Name]; ok {
-			continue
-		}
-
-		cache.Vars[*variable.Name] = AskQuestion(variable)
-	}
-
-	return nil
-}
-
 // SetDevSpaceRoot checks the current directory and all parent directories for a .devspace folder with a config and sets the current working directory accordingly
 func SetDevSpaceRoot() (bool, error) {
 	cwd, err := os.Getwd()
@@ -0,0 +1,37 @@
+package configutil
+
+import (
+	"testing"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/versions/latest"
+	"github.com/devspace-cloud/devspace/pkg/util/ptr"
+)
+
+func TestValidateOnUpload(t *testing.T) {
+	config := &latest.Config{
+		Dev: &latest.DevConfig{
+			Sync: &[]*latest.SyncConfig{
+				{
+					Selector: ptr.String("default"),
+					OnUpload: &[]*latest.SyncOnUpload{
+						{
+							Command: &[]*string{ptr.String("echo"), ptr.String("uploaded")},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	err := validate(config)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Null elements in the command are rejected
+	(*(*config.Dev.Sync)[0].OnUpload)[0].Command = &[]*string{ptr.String("echo"), nil}
+	err = validate(config)
+	if err == nil {
+		t.Fatal("Expected an error for a null element in the onUpload command")
+	}
+}
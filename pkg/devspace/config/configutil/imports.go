@@ -0,0 +1,99 @@
+package configutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/versions/latest"
+	"github.com/devspace-cloud/devspace/pkg/util/git"
+	"github.com/devspace-cloud/devspace/pkg/util/hash"
+	"github.com/devspace-cloud/devspace/pkg/util/log"
+)
+
+// ImportsFolder is the folder within the devspace root where imports from git repositories are cached
+const ImportsFolder = ".devspace/imports"
+
+// DefaultImportPath is the path of the partial within a git repository if no path is specified
+const DefaultImportPath = DefaultConfigPath
+
+// mergeImports loads all imported partials and merges them into the config in the given order
+func mergeImports(basePath string, config **latest.Config, imports []*latest.ImportConfig, recorder *stageRecorder, log log.Logger) error {
+	for index, importConfig := range imports {
+		importPath, err := getImportPath(basePath, importConfig, log)
+		if err != nil {
+			return fmt.Errorf("Error loading imports[%d]: %v", index, err)
+		}
+
+		importedConfig, err := loadConfigFromPath(importPath)
+		if err != nil {
+			return fmt.Errorf("Error loading imports[%d] from %s: %v", index, importPath, err)
+		}
+		if importedConfig.Imports != nil {
+			return fmt.Errorf("Error loading imports[%d] from %s: imported configs cannot import other configs", index, importPath)
+		}
+
+		Merge(config, importedConfig)
+		err = recorder.record(fmt.Sprintf("imports[%d]", index), *config)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getImportPath returns the local path of the import and clones the git repository of the import into the cache if necessary
+func getImportPath(basePath string, importConfig *latest.ImportConfig, log log.Logger) (string, error) {
+	if importConfig.Git == nil {
+		if importConfig.Path == nil {
+			return "", fmt.Errorf("path or git is required")
+		}
+		if importConfig.Ref != nil {
+			return "", fmt.Errorf("ref can only be used with git")
+		}
+
+		path := filepath.FromSlash(*importConfig.Path)
+		if filepath.IsAbs(path) {
+			return path, nil
+		}
+
+		return filepath.Join(basePath, path), nil
+	}
+
+	gitURL := strings.TrimSpace(*importConfig.Git)
+	ref := ""
+	if importConfig.Ref != nil {
+		ref = *importConfig.Ref
+	}
+
+	path := DefaultImportPath
+	if importConfig.Path != nil {
+		path = *importConfig.Path
+	}
+
+	// The cached copy of a pinned ref is reused until the cache is cleared with devspace update imports
+	localPath := filepath.Join(basePath, filepath.FromSlash(ImportsFolder), hash.String(gitURL+"@"+ref))
+	_, err := os.Stat(localPath)
+	if err != nil {
+		gitRepo := git.NewGitRepository(localPath, gitURL)
+		_, err = gitRepo.Update()
+		if err != nil {
+			os.RemoveAll(localPath)
+			return "", fmt.Errorf("Error cloning %s: %v", gitURL, err)
+		}
+
+		if ref != "" {
+			err = gitRepo.Checkout(ref)
+			if err != nil {
+				os.RemoveAll(localPath)
+				return "", err
+			}
+		}
+
+		log.Donef("Pulled import %s", gitURL)
+	}
+
+	return filepath.Join(localPath, filepath.FromSlash(path)), nil
+}
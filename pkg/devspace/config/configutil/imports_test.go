@@ -0,0 +1,115 @@
+package configutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/generated"
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/versions/latest"
+	"github.com/devspace-cloud/devspace/pkg/util/log"
+	"github.com/devspace-cloud/devspace/pkg/util/ptr"
+	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+func commitFile(t *testing.T, repo *git.Repository, dir, name, content string) string {
+	err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = worktree.Add(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash, err := worktree.Commit("update "+name, &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@test.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return hash.String()
+}
+
+func TestMergeImports(t *testing.T) {
+	generated.SetTestConfig(&generated.Config{
+		ActiveConfig: generated.DefaultConfigName,
+		Configs: map[string]*generated.CacheConfig{
+			generated.DefaultConfigName: generated.NewCache(),
+		},
+	})
+
+	basePath, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(basePath)
+
+	remotePath, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(remotePath)
+
+	// Create a git repository with two versions of the partial
+	repo, err := git.PlainInit(remotePath, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pinnedCommit := commitFile(t, repo, remotePath, "hooks.yaml", "hooks:\n- command: echo\n  args:\n  - pinned\n")
+	commitFile(t, repo, remotePath, "hooks.yaml", "hooks:\n- command: echo\n  args:\n  - latest\n")
+
+	err = ioutil.WriteFile(filepath.Join(basePath, "selectors.yaml"), []byte("dev:\n  selectors:\n  - name: default\n    labelSelector:\n      app: test\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := latest.New().(*latest.Config)
+	err = mergeImports(basePath, &config, []*latest.ImportConfig{
+		{Path: ptr.String("selectors.yaml")},
+		{Git: ptr.String(remotePath), Ref: ptr.String(pinnedCommit), Path: ptr.String("hooks.yaml")},
+	}, nil, log.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Dev == nil || config.Dev.Selectors == nil || len(*config.Dev.Selectors) != 1 || *(*config.Dev.Selectors)[0].Name != "default" {
+		t.Fatalf("Selectors were not imported: %#v", config.Dev)
+	}
+	if config.Hooks == nil || len(*config.Hooks) != 1 || *(*(*config.Hooks)[0].Args)[0] != "pinned" {
+		t.Fatalf("Hooks of the pinned commit were not imported: %#v", config.Hooks)
+	}
+
+	// The cached copy has to be reused
+	entries, err := ioutil.ReadDir(filepath.Join(basePath, filepath.FromSlash(ImportsFolder)))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("Expected one cached import, got %v (%v)", entries, err)
+	}
+
+	// Absolute import paths are not joined with the base path
+	importPath, err := getImportPath(remotePath, &latest.ImportConfig{Path: ptr.String(filepath.Join(basePath, "selectors.yaml"))}, log.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if importPath != filepath.Join(basePath, "selectors.yaml") {
+		t.Fatalf("Expected absolute import path %s, got %s", filepath.Join(basePath, "selectors.yaml"), importPath)
+	}
+
+	err = mergeImports(basePath, &config, []*latest.ImportConfig{{Ref: ptr.String("master")}}, nil, log.Discard)
+	if err == nil {
+		t.Fatal("Expected error for import without path and git")
+	}
+}
@@ -27,8 +27,8 @@ import (
 	yaml "gopkg.in/yaml.v2"
 )
 
-// VarMatchRegex is the regex to check if a value matches the devspace var format
-var VarMatchRegex = regexp.MustCompile("^(.*)(\\$\\{[^\\}]+\\})(.*)$")
+// VarMatchRegex is the regex to find devspace var expressions (${VAR}, ${VAR:-default} or ${VAR:?message}) in a value
+var VarMatchRegex = regexp.MustCompile("\\$\\{[^\\}]+\\}")
 
 // VarEnvPrefix is the prefix environment variables should have in order to use them
 const VarEnvPrefix = "DEVSPACE_VAR_"
@@ -140,40 +140,93 @@ func varReplaceFn(path, value string) interface{} {
 	// Save old value
 	LoadedVars[path] = value
 
-	matched := VarMatchRegex.FindStringSubmatch(value)
-	if len(matched) != 4 {
-		return ""
+	varName := ""
+	replaced := VarMatchRegex.ReplaceAllStringFunc(value, func(expression string) string {
+		var varValue string
+
+		varName, varValue = resolveVarExpression(expression[2 : len(expression)-1])
+		return varValue
+	})
+
+	// Only values that consist of a single variable are converted
+	if VarMatchRegex.FindString(value) == value {
+		return convertVarValue(varName, replaced)
+	}
+
+	return replaced
+}
+
+// resolveVarExpression resolves an expression of the form VAR, VAR:-default or VAR:?message
+// and returns the variable name and the value
+func resolveVarExpression(expression string) (string, string) {
+	varName, operator, argument := expression, "", ""
+	if index := strings.Index(expression, ":-"); index >= 0 {
+		varName, operator, argument = expression[:index], "-", expression[index+2:]
+	} else if index := strings.Index(expression, ":?"); index >= 0 {
+		varName, operator, argument = expression[:index], "?", expression[index+2:]
 	}
 
-	value = matched[2]
-	varName := strings.TrimSpace(value[2 : len(value)-1])
+	varName = strings.TrimSpace(varName)
+	varValue, found := getVarValue(varName, operator == "")
+	if found && varValue != "" {
+		return varName, varValue
+	}
 
-	// Find value for variable
-	varValue := ""
+	switch operator {
+	case "-":
+		return varName, argument
+	case "?":
+		if argument == "" {
+			argument = "value is required"
+		}
+
+		log.Fatalf("Variable %s is not set: %s", varName, argument)
+	}
+
+	return varName, varValue
+}
+
+// getVarValue returns the value of the variable. If ask is true, the user is asked for values
+// that are not set, otherwise false is returned for them
+func getVarValue(varName string, ask bool) (string, bool) {
 	if variable, ok := PredefinedVars[strings.ToUpper(varName)]; ok {
 		if variable.Value == nil {
+			if ask == false {
+				return "", false
+			}
+
 			log.Fatal(variable.ErrorMessage)
 		}
 
-		varValue = *variable.Value
+		return *variable.Value, true
 	} else if os.Getenv(VarEnvPrefix+strings.ToUpper(varName)) != "" {
-		envVarValue := os.Getenv(VarEnvPrefix + strings.ToUpper(varName))
-		varValue = envVarValue
-	} else {
-		generatedConfig, err := generated.LoadConfig()
+		return os.Getenv(VarEnvPrefix + strings.ToUpper(varName)), true
+	} else if definedValue, ok, err := getDefinedVarValue(varName); ok || err != nil {
 		if err != nil {
-			log.Fatalf("Error reading generated config: %v", err)
+			log.Fatal(err)
 		}
 
-		// Get current config
-		currentConfig := generatedConfig.GetActive()
-		if _, ok := currentConfig.Vars[varName]; !ok {
-			currentConfig.Vars[varName] = AskQuestion(&configs.Variable{
-				Question: ptr.String("Please enter a value for " + varName),
-			})
+		return definedValue, true
+	}
+
+	generatedConfig, err := generated.LoadConfig()
+	if err != nil {
+		log.Fatalf("Error reading generated config: %v", err)
+	}
+
+	// Get current config
+	currentConfig := generatedConfig.GetActive()
+	if _, ok := currentConfig.Vars[varName]; !ok {
+		if ask == false {
+			return "", false
 		}
 
-		varValue = currentConfig.Vars[varName]
+		currentConfig.Vars[varName], err = resolveVariable(".", varName, &configs.Variable{
+			Question: ptr.String("Please enter a value for " + varName),
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
 
 		// Save config
 		err = generated.SaveConfig(generatedConfig)
@@ -182,8 +235,35 @@ func varReplaceFn(path, value string) interface{} {
 		}
 	}
 
-	// Add matched groups again
-	varValue = matched[1] + varValue + matched[3]
+	return currentConfig.Vars[varName], true
+}
+
+// convertVarValue converts the value to the type of the variable. Values of variables without
+// type are converted to int or bool if possible
+func convertVarValue(varName, varValue string) interface{} {
+	varType := ""
+	if defined, ok := activeVarScope.definedVars[varName]; ok && defined.variable.Type != nil {
+		varType = *defined.variable.Type
+	}
+
+	switch varType {
+	case VarTypeString:
+		return varValue
+	case VarTypeInt:
+		i, err := strconv.Atoi(varValue)
+		if err != nil {
+			log.Fatalf("Variable %s has type %s, but its value %s is not an integer", varName, varType, varValue)
+		}
+
+		return i
+	case VarTypeBool:
+		b, err := strconv.ParseBool(varValue)
+		if err != nil {
+			log.Fatalf("Variable %s has type %s, but its value %s is not a boolean", varName, varType, varValue)
+		}
+
+		return b
+	}
 
 	// Check if we can convert val
 	if i, err := strconv.Atoi(varValue); err == nil {
@@ -215,6 +295,9 @@ func AskQuestion(variable *configs.Variable) string {
 		if variable.Default != nil {
 			params.DefaultValue = *variable.Default
 		}
+		if variable.Secret != nil && *variable.Secret {
+			params.IsPassword = true
+		}
 
 		if variable.Options != nil {
 			params.Options = *variable.Options
@@ -0,0 +1,183 @@
+package configutil
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/generated"
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/versions/latest"
+	"github.com/devspace-cloud/devspace/pkg/util/log"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// ValueOrigin describes where a value of a loaded config came from
+type ValueOrigin struct {
+	// Path is the path to the value, consisting of map keys (string) and list indices (int)
+	Path []interface{}
+
+	// Stage is the load stage that set the value last (base, imports[0], overrides[0] or profile NAME)
+	Stage string
+
+	// Var is the original value, if the value was set by a config variable
+	Var string
+}
+
+// stageRecorder records snapshots of the config after every load stage
+type stageRecorder struct {
+	stages []*configStage
+}
+
+type configStage struct {
+	name   string
+	values map[string]interface{}
+}
+
+func (r *stageRecorder) record(name string, config *latest.Config) error {
+	if r == nil {
+		return nil
+	}
+
+	values, err := getConfigValues(config)
+	if err != nil {
+		return err
+	}
+
+	r.stages = append(r.stages, &configStage{
+		name:   name,
+		values: values,
+	})
+	return nil
+}
+
+// GetConfigWithOrigins loads the config like GetConfigFromPath and additionally returns where every value came from
+func GetConfigWithOrigins(basePath string, loadConfig string, generatedConfig *generated.Config, log log.Logger) (*latest.Config, []*ValueOrigin, error) {
+	recorder := &stageRecorder{}
+
+	config, _, err := loadBaseConfigFromPath(basePath, loadConfig, true, generatedConfig, recorder, log)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = validate(config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error validating config in %s: %v", basePath, err)
+	}
+
+	rawConfig, err := toRawConfig(config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	origins := []*ValueOrigin{}
+	walkValues(rawConfig, []interface{}{}, func(path []interface{}, value interface{}) {
+		origin := &ValueOrigin{Path: path}
+
+		var lastValue interface{}
+		found := false
+		for _, stage := range recorder.stages {
+			stageValue, ok := stage.values[getVarPath(path)]
+			if ok && (found == false || reflect.DeepEqual(stageValue, lastValue) == false) {
+				origin.Stage = stage.name
+			}
+
+			lastValue, found = stageValue, ok
+		}
+
+		if loadedVar, ok := LoadedVars[getVarPath(path)]; ok {
+			origin.Var = loadedVar
+		}
+
+		origins = append(origins, origin)
+	})
+
+	return config, origins, nil
+}
+
+func toRawConfig(config *latest.Config) (interface{}, error) {
+	out, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var rawConfig interface{}
+	err = yaml.Unmarshal(out, &rawConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return rawConfig, nil
+}
+
+// getConfigValues returns all values of the config by their path
+func getConfigValues(config *latest.Config) (map[string]interface{}, error) {
+	rawConfig, err := toRawConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]interface{}{}
+	walkValues(rawConfig, []interface{}{}, func(path []interface{}, value interface{}) {
+		values[getVarPath(path)] = value
+	})
+
+	return values, nil
+}
+
+// walkValues calls fn for every scalar, empty map and empty list within value
+func walkValues(value interface{}, path []interface{}, fn func(path []interface{}, value interface{})) {
+	switch typedValue := value.(type) {
+	case map[interface{}]interface{}:
+		if len(typedValue) == 0 {
+			break
+		}
+
+		keys := make([]string, 0, len(typedValue))
+		for key := range typedValue {
+			keys = append(keys, fmt.Sprintf("%v", key))
+		}
+
+		sort.Strings(keys)
+		for _, key := range keys {
+			walkValues(lookupKey(typedValue, key), append(append([]interface{}{}, path...), key), fn)
+		}
+
+		return
+	case []interface{}:
+		if len(typedValue) == 0 {
+			break
+		}
+
+		for index, item := range typedValue {
+			walkValues(item, append(append([]interface{}{}, path...), index), fn)
+		}
+
+		return
+	}
+
+	fn(path, value)
+}
+
+func lookupKey(m map[interface{}]interface{}, key string) interface{} {
+	for k, v := range m {
+		if fmt.Sprintf("%v", k) == key {
+			return v
+		}
+	}
+
+	return nil
+}
+
+// getVarPath returns the path in the format of LoadedVars (e.g. .deployments[0].name)
+func getVarPath(path []interface{}) string {
+	varPath := ""
+	for _, segment := range path {
+		if index, ok := segment.(int); ok {
+			varPath += fmt.Sprintf("[%d]", index)
+		} else {
+			varPath += fmt.Sprintf(".%v", segment)
+		}
+	}
+
+	return varPath
+}
@@ -0,0 +1,119 @@
+package configutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/generated"
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/versions/latest"
+	"github.com/devspace-cloud/devspace/pkg/util/log"
+)
+
+func TestGetConfigWithOrigins(t *testing.T) {
+	basePath, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(basePath)
+
+	// Active profiles are only applied to the project in the working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	basePath, err = filepath.EvalSymlinks(basePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.Chdir(basePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Chdir(wd)
+
+	err = ioutil.WriteFile(filepath.Join(basePath, DefaultConfigsPath), []byte(`default:
+  config:
+    data:
+      version: `+latest.Version+`
+      images:
+        default:
+          image: ${ORIGIN_IMAGE}
+      cluster:
+        namespace: base
+        kubeContext: minikube
+  overrides:
+  - data:
+      cluster:
+        namespace: override
+        kubeContext: minikube
+  profiles:
+  - name: production
+    patches:
+    - op: replace
+      path: /cluster/namespace
+      value: production
+other:
+  config:
+    data:
+      version: `+latest.Version+`
+      cluster:
+        namespace: other
+        kubeContext: minikube
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	os.Setenv(VarEnvPrefix+"ORIGIN_IMAGE", "nginx")
+	defer os.Unsetenv(VarEnvPrefix + "ORIGIN_IMAGE")
+
+	generatedConfig := &generated.Config{
+		ActiveConfig:   generated.DefaultConfigName,
+		ActiveProfiles: []string{"production"},
+		Configs: map[string]*generated.CacheConfig{
+			generated.DefaultConfigName: generated.NewCache(),
+		},
+	}
+
+	// The active profiles belong to the active config only
+	config, err := GetConfigFromPath(basePath, "other", true, generatedConfig, log.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *config.Cluster.Namespace != "other" {
+		t.Fatalf("Expected namespace other, got %s", *config.Cluster.Namespace)
+	}
+
+	config, origins, err := GetConfigWithOrigins(basePath, generated.DefaultConfigName, generatedConfig, log.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *config.Cluster.Namespace != "production" {
+		t.Fatalf("Profile was not applied: %s", *config.Cluster.Namespace)
+	}
+
+	expected := map[string][2]string{
+		".version":              {"base", ""},
+		".images.default.image": {"base", "${ORIGIN_IMAGE}"},
+		".cluster.kubeContext":  {"base", ""},
+		".cluster.namespace":    {"profile production", ""},
+	}
+	for _, origin := range origins {
+		if expectedOrigin, ok := expected[getVarPath(origin.Path)]; ok {
+			if origin.Stage != expectedOrigin[0] || origin.Var != expectedOrigin[1] {
+				t.Fatalf("Wrong origin of %s: expected %v, got %s and %s", getVarPath(origin.Path), expectedOrigin, origin.Stage, origin.Var)
+			}
+
+			delete(expected, getVarPath(origin.Path))
+		}
+	}
+	if len(expected) > 0 {
+		t.Fatalf("Missing origins %v", expected)
+	}
+}
@@ -0,0 +1,398 @@
+package configutil
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/configs"
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/versions/latest"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Supported patch operations (RFC 6902)
+const (
+	PatchOpAdd     = "add"
+	PatchOpRemove  = "remove"
+	PatchOpReplace = "replace"
+	PatchOpMove    = "move"
+	PatchOpCopy    = "copy"
+	PatchOpTest    = "test"
+)
+
+// GetProfile returns the profile with the given name from the config definition
+func GetProfile(configDefinition *configs.ConfigDefinition, name string) (*configs.Profile, error) {
+	if configDefinition.Profiles != nil {
+		for _, profile := range *configDefinition.Profiles {
+			if profile.Name != nil && *profile.Name == name {
+				return profile, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("Profile %s couldn't be found", name)
+}
+
+// ValidateProfiles checks if all profiles of the config definition are valid
+func ValidateProfiles(configDefinition *configs.ConfigDefinition) error {
+	if configDefinition.Profiles == nil {
+		return nil
+	}
+
+	profileNames := map[string]bool{}
+	for index, profile := range *configDefinition.Profiles {
+		if profile.Name == nil {
+			return fmt.Errorf("profiles[%d].name is required", index)
+		}
+		if profileNames[*profile.Name] {
+			return fmt.Errorf("profiles[%d].name %s is used by multiple profiles", index, *profile.Name)
+		}
+
+		profileNames[*profile.Name] = true
+		if profile.Patches == nil {
+			continue
+		}
+
+		for patchIndex, patch := range *profile.Patches {
+			if patch.Operation == nil {
+				return fmt.Errorf("profiles[%d].patches[%d].op is required", index, patchIndex)
+			}
+			if patch.Path == nil || strings.HasPrefix(*patch.Path, "/") == false {
+				return fmt.Errorf("profiles[%d].patches[%d].path is required and has to start with /", index, patchIndex)
+			}
+
+			switch *patch.Operation {
+			case PatchOpAdd, PatchOpRemove, PatchOpReplace, PatchOpTest:
+			case PatchOpMove, PatchOpCopy:
+				if patch.From == nil || strings.HasPrefix(*patch.From, "/") == false {
+					return fmt.Errorf("profiles[%d].patches[%d].from is required for %s and has to start with /", index, patchIndex, *patch.Operation)
+				}
+			default:
+				return fmt.Errorf("profiles[%d].patches[%d].op %s is unknown", index, patchIndex, *patch.Operation)
+			}
+		}
+	}
+
+	return nil
+}
+
+// applyProfiles applies the patches of the given profiles in order to the config
+func applyProfiles(config *latest.Config, configDefinition *configs.ConfigDefinition, profiles []string) (*latest.Config, error) {
+	err := ValidateProfiles(configDefinition)
+	if err != nil {
+		return nil, err
+	}
+
+	yamlConfig, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var rawConfig interface{}
+	err = yaml.Unmarshal(yamlConfig, &rawConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, profileName := range profiles {
+		profile, err := GetProfile(configDefinition, profileName)
+		if err != nil {
+			return nil, err
+		}
+		if profile.Patches == nil {
+			continue
+		}
+
+		patches, err := resolvePatchVars(*profile.Patches)
+		if err != nil {
+			return nil, fmt.Errorf("Error resolving vars in profile %s: %v", profileName, err)
+		}
+
+		for index, patch := range patches {
+			rawConfig, err = ApplyPatch(rawConfig, patch)
+			if err != nil {
+				return nil, fmt.Errorf("Error applying patch %d of profile %s: %v", index, profileName, err)
+			}
+		}
+	}
+
+	yamlConfig, err = yaml.Marshal(rawConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	patchedConfig := latest.New().(*latest.Config)
+	err = yaml.UnmarshalStrict(yamlConfig, patchedConfig)
+	if err != nil {
+		return nil, fmt.Errorf("Patched config is invalid: %v", err)
+	}
+
+	return patchedConfig, nil
+}
+
+// resolvePatchVars replaces the config variables within the patches
+func resolvePatchVars(patches []*configs.PatchConfig) ([]*configs.PatchConfig, error) {
+	yamlPatches, err := yaml.Marshal(map[string]interface{}{"patches": patches})
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := resolveVars(yamlPatches)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := struct {
+		Patches []*configs.PatchConfig `yaml:"patches"`
+	}{}
+	err = yaml.Unmarshal(out, &resolved)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolved.Patches, nil
+}
+
+// ApplyPatch applies a single patch operation to the document and returns the changed document
+func ApplyPatch(doc interface{}, patch *configs.PatchConfig) (interface{}, error) {
+	if patch.Operation == nil || patch.Path == nil {
+		return nil, fmt.Errorf("op and path are required")
+	}
+
+	path, err := parsePatchPath(*patch.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch *patch.Operation {
+	case PatchOpAdd:
+		return patchAt(doc, path, func(parent interface{}, key string) (interface{}, error) {
+			return addValue(parent, key, patch.Value)
+		})
+	case PatchOpRemove:
+		return patchAt(doc, path, removeValue)
+	case PatchOpReplace:
+		return patchAt(doc, path, func(parent interface{}, key string) (interface{}, error) {
+			return replaceValue(parent, key, patch.Value)
+		})
+	case PatchOpTest:
+		value, err := getValue(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if reflect.DeepEqual(value, patch.Value) == false {
+			return nil, fmt.Errorf("Test failed: value at %s is %v and not %v", *patch.Path, value, patch.Value)
+		}
+
+		return doc, nil
+	case PatchOpMove, PatchOpCopy:
+		if patch.From == nil {
+			return nil, fmt.Errorf("from is required for %s", *patch.Operation)
+		}
+
+		from, err := parsePatchPath(*patch.From)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := getValue(doc, from)
+		if err != nil {
+			return nil, err
+		}
+
+		if *patch.Operation == PatchOpMove {
+			doc, err = patchAt(doc, from, removeValue)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			value = deepCopyValue(value)
+		}
+
+		return patchAt(doc, path, func(parent interface{}, key string) (interface{}, error) {
+			return addValue(parent, key, value)
+		})
+	}
+
+	return nil, fmt.Errorf("Unknown patch operation %s", *patch.Operation)
+}
+
+// parsePatchPath splits a json pointer into its unescaped segments
+func parsePatchPath(path string) ([]string, error) {
+	if strings.HasPrefix(path, "/") == false {
+		return nil, fmt.Errorf("Path %s has to start with /", path)
+	}
+
+	segments := strings.Split(path[1:], "/")
+	for i, segment := range segments {
+		segments[i] = strings.Replace(strings.Replace(segment, "~1", "/", -1), "~0", "~", -1)
+	}
+
+	return segments, nil
+}
+
+// patchAt calls apply with the parent of the value the path points to and replaces the parent
+// with the returned value
+func patchAt(node interface{}, path []string, apply func(parent interface{}, key string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return apply(node, path[0])
+	}
+
+	switch typedNode := node.(type) {
+	case map[interface{}]interface{}:
+		child, ok := typedNode[path[0]]
+		if ok == false {
+			return nil, fmt.Errorf("Key %s does not exist", path[0])
+		}
+
+		newChild, err := patchAt(child, path[1:], apply)
+		if err != nil {
+			return nil, err
+		}
+
+		typedNode[path[0]] = newChild
+		return typedNode, nil
+	case []interface{}:
+		index, err := getListIndex(typedNode, path[0])
+		if err != nil {
+			return nil, err
+		}
+
+		newChild, err := patchAt(typedNode[index], path[1:], apply)
+		if err != nil {
+			return nil, err
+		}
+
+		typedNode[index] = newChild
+		return typedNode, nil
+	}
+
+	return nil, fmt.Errorf("Cannot select %s in a value that is neither a map nor a list", path[0])
+}
+
+func getValue(node interface{}, path []string) (interface{}, error) {
+	for _, segment := range path {
+		switch typedNode := node.(type) {
+		case map[interface{}]interface{}:
+			child, ok := typedNode[segment]
+			if ok == false {
+				return nil, fmt.Errorf("Key %s does not exist", segment)
+			}
+
+			node = child
+		case []interface{}:
+			index, err := getListIndex(typedNode, segment)
+			if err != nil {
+				return nil, err
+			}
+
+			node = typedNode[index]
+		default:
+			return nil, fmt.Errorf("Cannot select %s in a value that is neither a map nor a list", segment)
+		}
+	}
+
+	return node, nil
+}
+
+func addValue(parent interface{}, key string, value interface{}) (interface{}, error) {
+	switch typedParent := parent.(type) {
+	case map[interface{}]interface{}:
+		typedParent[key] = value
+		return typedParent, nil
+	case []interface{}:
+		index := len(typedParent)
+		if key != "-" && key != strconv.Itoa(len(typedParent)) {
+			var err error
+			index, err = getListIndex(typedParent, key)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		newList := make([]interface{}, 0, len(typedParent)+1)
+		newList = append(newList, typedParent[:index]...)
+		newList = append(newList, value)
+		return append(newList, typedParent[index:]...), nil
+	}
+
+	return nil, fmt.Errorf("Cannot add %s to a value that is neither a map nor a list", key)
+}
+
+func replaceValue(parent interface{}, key string, value interface{}) (interface{}, error) {
+	switch typedParent := parent.(type) {
+	case map[interface{}]interface{}:
+		if _, ok := typedParent[key]; ok == false {
+			return nil, fmt.Errorf("Key %s does not exist", key)
+		}
+
+		typedParent[key] = value
+		return typedParent, nil
+	case []interface{}:
+		index, err := getListIndex(typedParent, key)
+		if err != nil {
+			return nil, err
+		}
+
+		typedParent[index] = value
+		return typedParent, nil
+	}
+
+	return nil, fmt.Errorf("Cannot replace %s in a value that is neither a map nor a list", key)
+}
+
+func removeValue(parent interface{}, key string) (interface{}, error) {
+	switch typedParent := parent.(type) {
+	case map[interface{}]interface{}:
+		if _, ok := typedParent[key]; ok == false {
+			return nil, fmt.Errorf("Key %s does not exist", key)
+		}
+
+		delete(typedParent, key)
+		return typedParent, nil
+	case []interface{}:
+		index, err := getListIndex(typedParent, key)
+		if err != nil {
+			return nil, err
+		}
+
+		return append(typedParent[:index:index], typedParent[index+1:]...), nil
+	}
+
+	return nil, fmt.Errorf("Cannot remove %s from a value that is neither a map nor a list", key)
+}
+
+// getListIndex returns the index of the list item the key refers to. The key is either an index
+// or name=VALUE, which selects the item whose name field equals VALUE
+func getListIndex(list []interface{}, key string) (int, error) {
+	if strings.HasPrefix(key, "name=") {
+		name := key[len("name="):]
+		for index, item := range list {
+			if itemMap, ok := item.(map[interface{}]interface{}); ok && fmt.Sprintf("%v", itemMap["name"]) == name {
+				return index, nil
+			}
+		}
+
+		return 0, fmt.Errorf("No list item with name %s found", name)
+	}
+
+	index, err := strconv.Atoi(key)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid list index %s", key)
+	}
+	if index < 0 || index >= len(list) {
+		return 0, fmt.Errorf("List index %d is out of range", index)
+	}
+
+	return index, nil
+}
+
+func deepCopyValue(value interface{}) interface{} {
+	yamlValue, _ := yaml.Marshal(value)
+
+	var copy interface{}
+	yaml.Unmarshal(yamlValue, &copy)
+
+	return copy
+}
@@ -0,0 +1,90 @@
+package configutil
+
+import (
+	"testing"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/configs"
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/generated"
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/versions/latest"
+	"github.com/devspace-cloud/devspace/pkg/util/ptr"
+)
+
+func TestApplyProfiles(t *testing.T) {
+	generated.SetTestConfig(&generated.Config{
+		ActiveConfig: generated.DefaultConfigName,
+		Configs: map[string]*generated.CacheConfig{
+			generated.DefaultConfigName: generated.NewCache(),
+		},
+	})
+
+	config := &latest.Config{
+		Version: ptr.String(latest.Version),
+		Deployments: &[]*latest.DeploymentConfig{
+			{
+				Name: ptr.String("frontend"),
+			},
+			{
+				Name:      ptr.String("backend"),
+				Namespace: ptr.String("dev"),
+			},
+			{
+				Name: ptr.String("database"),
+			},
+		},
+	}
+
+	configDefinition := &configs.ConfigDefinition{
+		Profiles: &[]*configs.Profile{
+			{
+				Name: ptr.String("staging"),
+				Patches: &[]*configs.PatchConfig{
+					{Operation: ptr.String(PatchOpTest), Path: ptr.String("/deployments/name=backend/namespace"), Value: "dev"},
+					{Operation: ptr.String(PatchOpReplace), Path: ptr.String("/deployments/name=backend/namespace"), Value: "staging"},
+					{Operation: ptr.String(PatchOpRemove), Path: ptr.String("/deployments/name=database")},
+				},
+			},
+			{
+				Name: ptr.String("ci"),
+				Patches: &[]*configs.PatchConfig{
+					{Operation: ptr.String(PatchOpAdd), Path: ptr.String("/deployments/1"), Value: map[interface{}]interface{}{"name": "tests"}},
+					{Operation: ptr.String(PatchOpCopy), From: ptr.String("/deployments/name=backend/namespace"), Path: ptr.String("/deployments/name=tests/namespace")},
+					{Operation: ptr.String(PatchOpMove), From: ptr.String("/deployments/0"), Path: ptr.String("/deployments/-")},
+				},
+			},
+		},
+	}
+
+	patchedConfig, err := applyProfiles(config, configDefinition, []string{"staging", "ci"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"tests:staging", "backend:staging", "frontend:"}
+	if len(*patchedConfig.Deployments) != len(expected) {
+		t.Fatalf("Expected %d deployments, got %d", len(expected), len(*patchedConfig.Deployments))
+	}
+	for index, deployment := range *patchedConfig.Deployments {
+		namespace := ""
+		if deployment.Namespace != nil {
+			namespace = *deployment.Namespace
+		}
+		if *deployment.Name+":"+namespace != expected[index] {
+			t.Fatalf("Expected deployment %d to be %s, got %s:%s", index, expected[index], *deployment.Name, namespace)
+		}
+	}
+
+	// The original config must not be changed
+	if *(*config.Deployments)[1].Namespace != "dev" || len(*config.Deployments) != 3 {
+		t.Fatal("Original config was changed")
+	}
+
+	_, err = applyProfiles(config, configDefinition, []string{"missing"})
+	if err == nil {
+		t.Fatal("Expected error for unknown profile")
+	}
+
+	_, err = applyProfiles(config, configDefinition, []string{"staging", "staging"})
+	if err == nil {
+		t.Fatal("Expected failing test operation")
+	}
+}
@@ -0,0 +1,165 @@
+package configutil
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/configs"
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/versions"
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/versions/latest"
+	"github.com/devspace-cloud/devspace/pkg/util/jsonschema"
+	"github.com/devspace-cloud/devspace/pkg/util/yamlutil"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// SchemaError is a json schema validation error within a config file
+type SchemaError struct {
+	File   string
+	Line   int
+	Column int
+
+	Err *jsonschema.Error
+}
+
+// Error implements the error interface
+func (e *SchemaError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%s: %v", e.File, e.Err)
+	}
+
+	return fmt.Sprintf("%s:%d:%d: %v", e.File, e.Line, e.Column, e.Err)
+}
+
+// ValidateSchema validates devspace.yaml or all configs and overrides of devspace-configs.yaml in the base
+// path against the json schema of their config version
+func ValidateSchema(basePath string) ([]*SchemaError, error) {
+	configsPath := filepath.Join(basePath, DefaultConfigsPath)
+
+	_, err := os.Stat(configsPath)
+	if err != nil {
+		return validateSchemaOfFile(filepath.Join(basePath, DefaultConfigPath), false)
+	}
+
+	content, err := ioutil.ReadFile(configsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	configDefinitions := configs.Configs{}
+	err = yaml.UnmarshalStrict(content, &configDefinitions)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing %s: %v", configsPath, err)
+	}
+
+	configNames := make([]string, 0, len(configDefinitions))
+	for configName := range configDefinitions {
+		configNames = append(configNames, configName)
+	}
+
+	sort.Strings(configNames)
+
+	schemaErrors := []*SchemaError{}
+	validatedFiles := map[string]bool{}
+	validate := func(configWrapper *configs.ConfigWrapper, prefix []interface{}, partial bool) error {
+		if configWrapper.Path != nil {
+			path := filepath.Join(basePath, filepath.FromSlash(*configWrapper.Path))
+			if validatedFiles[path] {
+				return nil
+			}
+
+			validatedFiles[path] = true
+			fileErrors, err := validateSchemaOfFile(path, partial)
+			if err != nil {
+				return err
+			}
+
+			schemaErrors = append(schemaErrors, fileErrors...)
+		} else if configWrapper.Data != nil {
+			schemaErrors = append(schemaErrors, validateSchemaOfData(configsPath, content, configWrapper.Data, prefix, partial)...)
+		}
+
+		return nil
+	}
+
+	for _, configName := range configNames {
+		configDefinition := configDefinitions[configName]
+		if configDefinition.Config != nil {
+			err = validate(configDefinition.Config, []interface{}{configName, "config", "data"}, false)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if configDefinition.Overrides != nil {
+			for index, override := range *configDefinition.Overrides {
+				err = validate(override, []interface{}{configName, "overrides", index, "data"}, true)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return schemaErrors, nil
+}
+
+func validateSchemaOfFile(path string, partial bool) ([]*SchemaError, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var data interface{}
+	err = yaml.Unmarshal(content, &data)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing %s: %v", path, err)
+	}
+
+	return validateSchemaOfData(path, content, data, []interface{}{}, partial), nil
+}
+
+// validateSchemaOfData validates the data that is located at prefix within the file content. Partial
+// configs (e.g. overrides) don't need to specify a version
+func validateSchemaOfData(file string, content []byte, data interface{}, prefix []interface{}, partial bool) []*SchemaError {
+	newError := func(path []interface{}, message string) *SchemaError {
+		line, column := yamlutil.FindPosition(content, append(append([]interface{}{}, prefix...), path...))
+		return &SchemaError{
+			File:   file,
+			Line:   line,
+			Column: column,
+			Err:    &jsonschema.Error{Path: path, Message: message},
+		}
+	}
+
+	dataMap, ok := data.(map[interface{}]interface{})
+	if ok == false {
+		return []*SchemaError{newError([]interface{}{}, "config has to be an object")}
+	}
+
+	version, ok := dataMap["version"].(string)
+	if ok == false {
+		if _, exists := dataMap["version"]; exists || partial == false {
+			return []*SchemaError{newError([]interface{}{"version"}, "version is required and has to be a string")}
+		}
+
+		version = latest.Version
+		dataMap["version"] = version
+	}
+
+	schema, err := versions.GetSchema(version)
+	if err != nil {
+		return []*SchemaError{newError([]interface{}{"version"}, err.Error())}
+	}
+
+	schemaErrors := []*SchemaError{}
+	for _, validationError := range jsonschema.Validate(schema, dataMap) {
+		schemaError := newError(validationError.Path, validationError.Message)
+		schemaError.Err = validationError
+		schemaErrors = append(schemaErrors, schemaError)
+	}
+
+	return schemaErrors
+}
@@ -0,0 +1,52 @@
+package configutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateSchemaComponentVolumes(t *testing.T) {
+	basePath, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(basePath)
+
+	config := `version: v1beta2
+deployments:
+- name: my-component
+  component:
+    containers:
+    - image: nginx
+      volumeMounts:
+      - containerPath: /etc/nginx
+        volume:
+          name: nginx-config
+    volumes:
+    - name: mysql-data
+      size: "5Gi"
+    - name: nginx-config
+      configMap:
+        name: my-configmap
+    - name: secret-token
+      secret:
+        secretName: my-secret
+        defaultMode: 0644
+`
+
+	err = ioutil.WriteFile(filepath.Join(basePath, DefaultConfigPath), []byte(config), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schemaErrors, err := ValidateSchema(basePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schemaErrors) != 0 {
+		t.Fatalf("Expected no errors for component volumes, got %v", schemaErrors)
+	}
+}
@@ -0,0 +1,389 @@
+package configutil
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/configs"
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/generated"
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/secrets"
+	"github.com/devspace-cloud/devspace/pkg/util/envutil"
+	"github.com/devspace-cloud/devspace/pkg/util/log"
+	"github.com/devspace-cloud/devspace/pkg/util/terminal"
+)
+
+// Cache policies of variables
+const (
+	// VarCacheAlways saves the value in the generated config and reuses it in every following run
+	VarCacheAlways = "always"
+	// VarCacheOnce resolves the value once per run without saving it
+	VarCacheOnce = "once"
+	// VarCacheNever resolves the value every time the variable is used
+	VarCacheNever = "never"
+)
+
+// Types of variables
+const (
+	VarTypeString = "string"
+	VarTypeInt    = "int"
+	VarTypeBool   = "bool"
+)
+
+// definedVariable is a variable that was defined in the configs or vars file
+type definedVariable struct {
+	variable *configs.Variable
+	basePath string
+}
+
+// varScope holds the variables of a single config
+type varScope struct {
+	// definedVars holds all defined variables by name
+	definedVars map[string]*definedVariable
+
+	// onceVars holds the values of variables with cache policy once
+	onceVars map[string]string
+}
+
+// varScopes holds the variable scopes by base path and config name, so that the configs of dependencies
+// don't override the variables of the config that depends on them
+var varScopes = map[string]*varScope{}
+
+// activeVarScope is the variable scope of the config that is currently loaded
+var activeVarScope = getVarScope(".", generated.DefaultConfigName)
+
+// loadedVarScope is the variable scope of the config loaded by GetConfig
+var loadedVarScope = activeVarScope
+
+// getVarScope returns the variable scope of the config in the base path
+func getVarScope(basePath, configName string) *varScope {
+	absPath, err := filepath.Abs(basePath)
+	if err != nil {
+		absPath = filepath.Clean(basePath)
+	}
+
+	key := absPath + ":" + configName
+	if _, ok := varScopes[key]; ok == false {
+		varScopes[key] = &varScope{
+			definedVars: map[string]*definedVariable{},
+			onceVars:    map[string]string{},
+		}
+	}
+
+	return varScopes[key]
+}
+
+// useVarScope activates the variable scope of the config in the base path and returns a function
+// that restores the previous scope
+func useVarScope(basePath, configName string) func() {
+	previous := activeVarScope
+	activeVarScope = getVarScope(basePath, configName)
+
+	return func() {
+		activeVarScope = previous
+	}
+}
+
+// isInteractive is used to check if the user can be asked for variable values
+var isInteractive = terminal.IsInteractive
+
+// resolveDefinedVars validates the variable definitions and resolves all variables that are not
+// resolved during usage
+func resolveDefinedVars(basePath, configName string, cache *generated.CacheConfig, vars []*configs.Variable) error {
+	for idx, variable := range vars {
+		err := validateVariable(idx, variable)
+		if err != nil {
+			return err
+		}
+
+		name := *variable.Name
+		activeVarScope.definedVars[name] = &definedVariable{
+			variable: variable,
+			basePath: basePath,
+		}
+
+		if value := os.Getenv(VarEnvPrefix + strings.ToUpper(name)); value != "" {
+			if isSecret(variable) {
+				log.Redact(value)
+			}
+
+			continue
+		}
+
+		switch getCachePolicy(variable) {
+		case VarCacheAlways:
+			if isSecret(variable) {
+				value, err := resolveSecretVariable(basePath, configName, name, variable, cache)
+				if err != nil {
+					return err
+				}
+
+				activeVarScope.onceVars[name] = value
+				continue
+			}
+			if _, ok := cache.Vars[name]; ok {
+				continue
+			}
+
+			value, err := resolveVariable(basePath, name, variable)
+			if err != nil {
+				return err
+			}
+
+			cache.Vars[name] = value
+		case VarCacheOnce:
+			// Values of variables that are not cached always must not stay in the generated config
+			delete(cache.Vars, name)
+
+			value, err := resolveVariable(basePath, name, variable)
+			if err != nil {
+				return err
+			}
+
+			activeVarScope.onceVars[name] = value
+		case VarCacheNever:
+			delete(cache.Vars, name)
+		}
+	}
+
+	return nil
+}
+
+func validateVariable(idx int, variable *configs.Variable) error {
+	if variable.Name == nil {
+		return fmt.Errorf("Name required for variable with index %d", idx)
+	}
+
+	if variable.Source != nil {
+		sources := 0
+		for _, source := range []*string{variable.Source.Command, variable.Source.File, variable.Source.EnvFile} {
+			if source != nil {
+				sources++
+			}
+		}
+		if sources != 1 {
+			return fmt.Errorf("Exactly one of command, file and envFile has to be specified as source of variable %s", *variable.Name)
+		}
+		if variable.Source.Key != nil && variable.Source.EnvFile == nil {
+			return fmt.Errorf("Source key of variable %s can only be used with envFile", *variable.Name)
+		}
+	}
+
+	if variable.Type != nil {
+		switch *variable.Type {
+		case VarTypeString, VarTypeInt, VarTypeBool:
+		default:
+			return fmt.Errorf("Unknown type %s of variable %s, use one of %s, %s or %s", *variable.Type, *variable.Name, VarTypeString, VarTypeInt, VarTypeBool)
+		}
+	}
+
+	if variable.Cache != nil {
+		switch *variable.Cache {
+		case VarCacheAlways, VarCacheOnce, VarCacheNever:
+		default:
+			return fmt.Errorf("Unknown cache policy %s of variable %s, use one of %s, %s or %s", *variable.Cache, *variable.Name, VarCacheAlways, VarCacheOnce, VarCacheNever)
+		}
+	}
+
+	return nil
+}
+
+// getCachePolicy returns the cache policy of the variable. Answers to questions are cached always by
+// default, values from sources once
+func getCachePolicy(variable *configs.Variable) string {
+	if variable.Cache != nil {
+		return *variable.Cache
+	}
+	if variable.Source != nil {
+		return VarCacheOnce
+	}
+
+	return VarCacheAlways
+}
+
+// isSecret checks if the variable is marked as secret
+func isSecret(variable *configs.Variable) bool {
+	return variable.Secret != nil && *variable.Secret
+}
+
+// GetSecretVars returns the names of all defined secret variables of the loaded config
+func GetSecretVars() []string {
+	names := []string{}
+	for name, defined := range loadedVarScope.definedVars {
+		if isSecret(defined.variable) {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// getDefinedVarValue returns the value of a defined variable that is not cached in the generated config
+func getDefinedVarValue(name string) (string, bool, error) {
+	defined, ok := activeVarScope.definedVars[name]
+	if ok == false {
+		return "", false, nil
+	}
+
+	policy := getCachePolicy(defined.variable)
+	if policy == VarCacheAlways && isSecret(defined.variable) {
+		// Secret values are loaded from the secret store in resolveDefinedVars
+		policy = VarCacheOnce
+	}
+
+	switch policy {
+	case VarCacheOnce:
+		if value, ok := activeVarScope.onceVars[name]; ok {
+			return value, true, nil
+		}
+
+		value, err := resolveVariable(defined.basePath, name, defined.variable)
+		if err != nil {
+			return "", false, err
+		}
+
+		activeVarScope.onceVars[name] = value
+		return value, true, nil
+	case VarCacheNever:
+		value, err := resolveVariable(defined.basePath, name, defined.variable)
+		if err != nil {
+			return "", false, err
+		}
+
+		return value, true, nil
+	}
+
+	return "", false, nil
+}
+
+// resolveVariable loads the value of the variable from its source or asks the user for it. Values of
+// secret variables are redacted from all logs
+func resolveVariable(basePath, name string, variable *configs.Variable) (string, error) {
+	value, err := loadVariableValue(basePath, name, variable)
+	if err != nil {
+		return "", err
+	}
+	if isSecret(variable) {
+		log.Redact(value)
+	}
+
+	return value, nil
+}
+
+func loadVariableValue(basePath, name string, variable *configs.Variable) (string, error) {
+	if variable.Source == nil {
+		if isInteractive() == false {
+			if variable.Default != nil {
+				return *variable.Default, nil
+			}
+
+			return "", fmt.Errorf("Cannot ask for the value of variable %s in a non-interactive terminal. Please set the environment variable %s or define a default or source for the variable", name, VarEnvPrefix+strings.ToUpper(name))
+		}
+
+		return AskQuestion(variable), nil
+	}
+
+	if variable.Source.Command != nil {
+		return runVariableCommand(basePath, name, *variable.Source.Command)
+	}
+
+	if variable.Source.File != nil {
+		content, err := ioutil.ReadFile(filepath.Join(basePath, filepath.FromSlash(*variable.Source.File)))
+		if err != nil {
+			return "", fmt.Errorf("Error reading source file of variable %s: %v", name, err)
+		}
+
+		return strings.TrimRight(string(content), "\r\n"), nil
+	}
+
+	values, err := envutil.ParseEnvFile(filepath.Join(basePath, filepath.FromSlash(*variable.Source.EnvFile)))
+	if err != nil {
+		return "", fmt.Errorf("Error loading env file of variable %s: %v", name, err)
+	}
+
+	key := name
+	if variable.Source.Key != nil {
+		key = *variable.Source.Key
+	}
+	if value, ok := values[key]; ok {
+		return value, nil
+	}
+	if variable.Default != nil {
+		return *variable.Default, nil
+	}
+
+	return "", fmt.Errorf("Key %s of variable %s couldn't be found in %s", key, name, *variable.Source.EnvFile)
+}
+
+func runVariableCommand(basePath, name, command string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+
+	stderr := &bytes.Buffer{}
+	cmd.Dir = basePath
+	cmd.Stderr = stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("Error running source command of variable %s: %v\n%s", name, err, stderr.String())
+	}
+
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
+
+// resolveSecretVariable loads the value of a secret variable from the secret store of the user or resolves
+// and stores it. Plaintext values of variables that were cached before they were marked as secret are moved
+// into the store
+func resolveSecretVariable(basePath, configName, name string, variable *configs.Variable, cache *generated.CacheConfig) (string, error) {
+	store, err := secrets.Load()
+	if err != nil {
+		return "", fmt.Errorf("Error loading secret store: %v", err)
+	}
+
+	projectID, err := secrets.ProjectID(basePath)
+	if err != nil {
+		return "", err
+	}
+
+	value, ok, err := store.Get(projectID, configName, name)
+	if err != nil {
+		return "", err
+	} else if ok {
+		log.Redact(value)
+		return value, nil
+	}
+
+	value, ok = cache.Vars[name]
+	if ok {
+		log.Redact(value)
+		delete(cache.Vars, name)
+	} else {
+		value, err = resolveVariable(basePath, name, variable)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	err = store.Set(projectID, configName, name, value)
+	if err != nil {
+		return "", fmt.Errorf("Error encrypting secret %s: %v", name, err)
+	}
+
+	err = store.Save()
+	if err != nil {
+		return "", fmt.Errorf("Error saving secret store: %v", err)
+	}
+
+	return value, nil
+}
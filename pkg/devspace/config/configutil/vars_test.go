@@ -0,0 +1,231 @@
+package configutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/configs"
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/generated"
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/secrets"
+	"github.com/devspace-cloud/devspace/pkg/util/ptr"
+	"github.com/devspace-cloud/devspace/pkg/util/terminal"
+)
+
+func TestResolveDefinedVars(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping test on windows")
+	}
+
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	err = ioutil.WriteFile(filepath.Join(dir, "token"), []byte("secret-token\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ioutil.WriteFile(filepath.Join(dir, ".env"), []byte("# comment\nexport DB_USER=admin\nDB_PASSWORD=\"pass word\"\nDB_PORT=5432 # port\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	isInteractive = func() bool { return false }
+	defer func() { isInteractive = terminal.IsInteractive }()
+
+	vars := []*configs.Variable{
+		{Name: ptr.String("COMMIT"), Source: &configs.VariableSource{Command: ptr.String("echo abc123")}},
+		{Name: ptr.String("TOKEN"), Source: &configs.VariableSource{File: ptr.String("token")}, Cache: ptr.String(VarCacheAlways)},
+		{Name: ptr.String("DB_USER"), Source: &configs.VariableSource{EnvFile: ptr.String(".env")}},
+		{Name: ptr.String("PASSWORD"), Source: &configs.VariableSource{EnvFile: ptr.String(".env"), Key: ptr.String("DB_PASSWORD")}, Cache: ptr.String(VarCacheNever)},
+		{Name: ptr.String("REGION"), Default: ptr.String("eu-west-1")},
+	}
+
+	cache := generated.NewCache()
+	cache.Vars["COMMIT"] = "stale"
+
+	err = resolveDefinedVars(dir, generated.DefaultConfigName, cache, vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cache.Vars) != 2 || cache.Vars["TOKEN"] != "secret-token" || cache.Vars["REGION"] != "eu-west-1" {
+		t.Fatalf("Unexpected cached vars %#v", cache.Vars)
+	}
+
+	expected := map[string]string{
+		"COMMIT":   "abc123",
+		"DB_USER":  "admin",
+		"PASSWORD": "pass word",
+	}
+	for name, expectedValue := range expected {
+		value, ok, err := getDefinedVarValue(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok == false || value != expectedValue {
+			t.Fatalf("Expected %s to be %s, got %s", name, expectedValue, value)
+		}
+	}
+
+	// Variables without default must not block in a non-interactive terminal
+	err = resolveDefinedVars(dir, generated.DefaultConfigName, cache, []*configs.Variable{{Name: ptr.String("MISSING")}})
+	if err == nil {
+		t.Fatal("Expected error for variable without value in a non-interactive terminal")
+	}
+
+	err = resolveDefinedVars(dir, generated.DefaultConfigName, cache, []*configs.Variable{{Name: ptr.String("INVALID"), Source: &configs.VariableSource{Command: ptr.String("true"), File: ptr.String("token")}}})
+	if err == nil {
+		t.Fatal("Expected error for variable with multiple sources")
+	}
+}
+
+func TestVarReplaceFn(t *testing.T) {
+	generated.SetTestConfig(&generated.Config{
+		ActiveConfig: generated.DefaultConfigName,
+		Configs: map[string]*generated.CacheConfig{
+			generated.DefaultConfigName: generated.NewCache(),
+		},
+	})
+
+	os.Setenv(VarEnvPrefix+"VERSION", "1")
+	os.Setenv(VarEnvPrefix+"REPLICAS", "2")
+	os.Setenv(VarEnvPrefix+"NAME", "app")
+	defer os.Unsetenv(VarEnvPrefix + "VERSION")
+	defer os.Unsetenv(VarEnvPrefix + "REPLICAS")
+	defer os.Unsetenv(VarEnvPrefix + "NAME")
+
+	activeVarScope.definedVars["VERSION"] = &definedVariable{variable: &configs.Variable{Name: ptr.String("VERSION"), Type: ptr.String(VarTypeString)}}
+	defer delete(activeVarScope.definedVars, "VERSION")
+
+	testCases := map[string]interface{}{
+		"${VERSION}":                  "1",
+		"${REPLICAS}":                 2,
+		"${NAME}-${VERSION}":          "app-1",
+		"${ UNSET_VAR :-fallback}":    "fallback",
+		"${UNSET_VAR:-}":              "",
+		"${NAME:-other}":              "app",
+		"${UNSET_VAR:-3}":             3,
+		"${NAME:?name is required}/x": "app/x",
+	}
+
+	for value, expected := range testCases {
+		replaced := varReplaceFn("path", value)
+		if replaced != expected {
+			t.Fatalf("Expected %s to be replaced with %#v, got %#v", value, expected, replaced)
+		}
+	}
+}
+
+func TestResolveSecretVars(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	oldStorePath, oldKeyPath := secrets.StorePath, secrets.KeyPath
+	secrets.StorePath, secrets.KeyPath = filepath.Join(dir, "secrets.yaml"), filepath.Join(dir, "secrets.key")
+	defer func() { secrets.StorePath, secrets.KeyPath = oldStorePath, oldKeyPath }()
+
+	isInteractive = func() bool { return false }
+	defer func() { isInteractive = terminal.IsInteractive }()
+
+	vars := []*configs.Variable{{Name: ptr.String("API_KEY"), Secret: ptr.Bool(true)}}
+
+	// Plaintext values have to be moved into the secret store
+	cache := generated.NewCache()
+	cache.Vars["API_KEY"] = "plain-secret"
+
+	err = resolveDefinedVars(dir, generated.DefaultConfigName, cache, vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.Vars["API_KEY"]; ok {
+		t.Fatal("Secret value was not removed from the generated config")
+	}
+
+	content, err := ioutil.ReadFile(secrets.StorePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(content), "plain-secret") {
+		t.Fatal("Secret value was stored unencrypted")
+	}
+
+	delete(activeVarScope.onceVars, "API_KEY")
+	err = resolveDefinedVars(dir, generated.DefaultConfigName, generated.NewCache(), vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	value, ok, err := getDefinedVarValue("API_KEY")
+	if err != nil || ok == false || value != "plain-secret" {
+		t.Fatalf("Expected plain-secret from the secret store, got %s (%v)", value, err)
+	}
+	if names := GetSecretVars(); len(names) != 1 || names[0] != "API_KEY" {
+		t.Fatalf("Unexpected secret vars %v", names)
+	}
+}
+
+func TestVarScopes(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping test on windows")
+	}
+
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	isInteractive = func() bool { return false }
+	defer func() { isInteractive = terminal.IsInteractive }()
+
+	dependencyPath := filepath.Join(dir, "dependency")
+	err = os.Mkdir(dependencyPath, 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vars := map[string][]*configs.Variable{
+		dir:            {{Name: ptr.String("IMAGE"), Source: &configs.VariableSource{Command: ptr.String("echo parent")}, Type: ptr.String(VarTypeString)}},
+		dependencyPath: {{Name: ptr.String("IMAGE"), Source: &configs.VariableSource{Command: ptr.String("echo 1")}, Type: ptr.String(VarTypeInt)}},
+	}
+
+	// The dependency defines the same variable while the parent config is loaded
+	restore := useVarScope(dir, generated.DefaultConfigName)
+	err = resolveDefinedVars(dir, generated.DefaultConfigName, generated.NewCache(), vars[dir])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restoreDependency := useVarScope(dependencyPath, generated.DefaultConfigName)
+	err = resolveDefinedVars(dependencyPath, generated.DefaultConfigName, generated.NewCache(), vars[dependencyPath])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	value, ok, err := getDefinedVarValue("IMAGE")
+	if err != nil || ok == false || value != "1" || convertVarValue("IMAGE", value) != 1 {
+		t.Fatalf("Expected IMAGE of the dependency to be 1, got %s (%v)", value, err)
+	}
+
+	restoreDependency()
+
+	value, ok, err = getDefinedVarValue("IMAGE")
+	if err != nil || ok == false || value != "parent" || convertVarValue("IMAGE", "1") != "1" {
+		t.Fatalf("Expected IMAGE of the parent config to be parent, got %s (%v)", value, err)
+	}
+
+	restore()
+}
@@ -1,11 +1,13 @@
 package generated
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"sync"
 
+	"github.com/devspace-cloud/devspace/pkg/util/fsutil"
 	yaml "gopkg.in/yaml.v2"
 )
 
@@ -14,9 +16,14 @@ const DefaultConfigName = "default"
 
 // Config specifies the runtime config struct
 type Config struct {
-	ActiveConfig string                  `yaml:"activeConfig,omitempty"`
-	Configs      map[string]*CacheConfig `yaml:"configs,omitempty"`
-	CloudSpace   *CloudSpaceConfig       `yaml:"space,omitempty"`
+	ActiveConfig   string                  `yaml:"activeConfig,omitempty"`
+	ActiveProfiles []string                `yaml:"activeProfiles,omitempty"`
+	Configs        map[string]*CacheConfig `yaml:"configs,omitempty"`
+	CloudSpace     *CloudSpaceConfig       `yaml:"space,omitempty"`
+
+	// base is a copy of the config as it was loaded or last saved and is used to find the changes of
+	// this process when saving
+	base *Config
 }
 
 // CloudSpaceConfig holds all the informations about a certain cloud space
@@ -113,6 +120,13 @@ func LoadConfigFromPath(path string) (*Config, error) {
 	}
 
 	InitDevSpaceConfig(loadedConfig, loadedConfig.ActiveConfig)
+
+	base, err := copyConfig(loadedConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	loadedConfig.base = base
 	return loadedConfig, nil
 }
 
@@ -171,23 +185,40 @@ func InitDevSpaceConfig(config *Config, configName string) {
 	}
 }
 
-// SaveConfig saves the config to the filesystem
+// SaveConfig saves the config to the filesystem. Other processes might have saved the config since it was
+// loaded, so the config is reloaded while holding the lock and only the changes of this process are applied
 func SaveConfig(config *Config) error {
 	if testDontSaveConfig {
 		return nil
 	}
 
 	workdir, _ := os.Getwd()
-	data, err := yaml.Marshal(config)
+	configPath := filepath.Join(workdir, ConfigPath)
+
+	lock, err := fsutil.Lock(configPath)
+	if err != nil {
+		return fmt.Errorf("Error locking %s: %v", configPath, err)
+	}
+
+	defer lock.Unlock()
+
+	current, err := LoadConfigFromPath(configPath)
 	if err != nil {
 		return err
 	}
 
-	configPath := filepath.Join(workdir, ConfigPath)
-	err = os.MkdirAll(filepath.Dir(configPath), 0755)
+	mergeConfig(config.base, config, current)
+
+	data, err := yaml.Marshal(current)
+	if err != nil {
+		return err
+	}
+
+	err = fsutil.WriteFileAtomic(data, configPath, 0666)
 	if err != nil {
 		return err
 	}
 
-	return ioutil.WriteFile(configPath, data, 0666)
+	config.base, err = copyConfig(config)
+	return err
 }
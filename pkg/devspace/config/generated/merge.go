@@ -0,0 +1,86 @@
+package generated
+
+import (
+	"reflect"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// copyConfig returns a deep copy of the config
+func copyConfig(config *Config) (*Config, error) {
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+
+	copied := &Config{}
+	err = yaml.Unmarshal(data, copied)
+	if err != nil {
+		return nil, err
+	}
+	if copied.Configs == nil {
+		copied.Configs = make(map[string]*CacheConfig)
+	}
+
+	return copied, nil
+}
+
+// mergeConfig applies the changes that were made to config since it was loaded as base to current, which
+// is the config that was saved in the meantime (e.g. by another devspace process). Cache entries that
+// were not changed in config keep the value of current
+func mergeConfig(base, config, current *Config) {
+	if base == nil {
+		base = &Config{Configs: make(map[string]*CacheConfig)}
+	}
+
+	if config.ActiveConfig != base.ActiveConfig {
+		current.ActiveConfig = config.ActiveConfig
+	}
+	if reflect.DeepEqual(config.ActiveProfiles, base.ActiveProfiles) == false {
+		current.ActiveProfiles = config.ActiveProfiles
+	}
+	if reflect.DeepEqual(config.CloudSpace, base.CloudSpace) == false {
+		current.CloudSpace = config.CloudSpace
+	}
+
+	for configName, cache := range config.Configs {
+		baseCache := base.Configs[configName]
+		if baseCache == nil {
+			baseCache = &CacheConfig{}
+		}
+
+		InitDevSpaceConfig(current, configName)
+		currentCache := current.Configs[configName]
+
+		mergeMap(baseCache.Deployments, cache.Deployments, currentCache.Deployments)
+		mergeMap(baseCache.Images, cache.Images, currentCache.Images)
+		mergeMap(baseCache.Dependencies, cache.Dependencies, currentCache.Dependencies)
+		mergeMap(baseCache.Vars, cache.Vars, currentCache.Vars)
+	}
+	for configName := range base.Configs {
+		if _, ok := config.Configs[configName]; ok == false {
+			delete(current.Configs, configName)
+		}
+	}
+}
+
+// mergeMap sets all entries that were added or changed in config since base in current and deletes the
+// entries that were removed. All maps have to be of the same type
+func mergeMap(base, config, current interface{}) {
+	baseMap := reflect.ValueOf(base)
+	configMap := reflect.ValueOf(config)
+	currentMap := reflect.ValueOf(current)
+
+	for _, key := range configMap.MapKeys() {
+		value := configMap.MapIndex(key)
+		baseValue := baseMap.MapIndex(key)
+		if baseValue.IsValid() == false || reflect.DeepEqual(baseValue.Interface(), value.Interface()) == false {
+			currentMap.SetMapIndex(key, value)
+		}
+	}
+	for _, key := range baseMap.MapKeys() {
+		if configMap.MapIndex(key).IsValid() == false {
+			currentMap.SetMapIndex(key, reflect.Value{})
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package generated
+
+import (
+	"io/ioutil"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestSaveConfigMergesConcurrentChanges(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.Chdir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Chdir(wd)
+
+	initial, err := LoadConfigFromPath(ConfigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	initial.GetActive().Vars["REMOVED"] = "value"
+	initial.GetActive().Vars["KEPT"] = "value"
+	err = SaveConfig(initial)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := LoadConfigFromPath(ConfigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := LoadConfigFromPath(ConfigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first.GetActive().GetImageCache("default").Tag = "abc"
+	delete(first.GetActive().Vars, "REMOVED")
+	second.GetActive().GetDeploymentCache("default").HelmChartHash = "123"
+
+	err = SaveConfig(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = SaveConfig(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Save concurrently
+	waitGroup := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		waitGroup.Add(1)
+		go func(i int) {
+			defer waitGroup.Done()
+
+			config, err := LoadConfigFromPath(ConfigPath)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			config.GetActive().Dependencies[strconv.Itoa(i)] = "hash"
+			err = SaveConfig(config)
+			if err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+
+	waitGroup.Wait()
+
+	result, err := LoadConfigFromPath(ConfigPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache := result.GetActive()
+	if cache.Images["default"] == nil || cache.Images["default"].Tag != "abc" {
+		t.Fatalf("Image cache of the first config was lost: %#v", cache.Images)
+	}
+	if cache.Deployments["default"] == nil || cache.Deployments["default"].HelmChartHash != "123" {
+		t.Fatalf("Deployment cache of the second config was lost: %#v", cache.Deployments)
+	}
+	if _, ok := cache.Vars["REMOVED"]; ok || cache.Vars["KEPT"] != "value" {
+		t.Fatalf("Unexpected vars %#v", cache.Vars)
+	}
+	if len(cache.Dependencies) != 10 {
+		t.Fatalf("Expected 10 dependencies, got %#v", cache.Dependencies)
+	}
+}
@@ -0,0 +1,218 @@
+package secrets
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/devspace-cloud/devspace/pkg/util/encryption"
+	"github.com/devspace-cloud/devspace/pkg/util/fsutil"
+	"github.com/devspace-cloud/devspace/pkg/util/hash"
+	homedir "github.com/mitchellh/go-homedir"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// StoreFile is the file in the home directory of the user where encrypted secrets are stored
+const StoreFile = ".devspace/secrets.yaml"
+
+// KeyFile is the file in the home directory of the user that holds the key for the secrets
+const KeyFile = ".devspace/secrets.key"
+
+// StorePath and KeyPath will be filled during init
+var StorePath, KeyPath string
+
+func init() {
+	homedir, _ := homedir.Dir()
+
+	StorePath = filepath.Join(homedir, filepath.FromSlash(StoreFile))
+	KeyPath = filepath.Join(homedir, filepath.FromSlash(KeyFile))
+}
+
+// Store holds the encrypted secret values of all projects of the user by project id, config name and
+// variable name
+type Store struct {
+	Projects map[string]map[string]map[string]string `yaml:"projects,omitempty"`
+
+	key []byte
+
+	// changes holds the values that were set since the store was loaded
+	changes []*change
+}
+
+type change struct {
+	projectID  string
+	configName string
+	name       string
+	encrypted  string
+}
+
+// Load loads the secret store of the user and creates a new key if there is none yet
+func Load() (*Store, error) {
+	// Other devspace processes could create the key or write the store at the same time
+	lock, err := fsutil.Lock(StorePath)
+	if err != nil {
+		return nil, fmt.Errorf("Error locking %s: %v", StorePath, err)
+	}
+
+	defer lock.Unlock()
+
+	key, err := loadKey()
+	if err != nil {
+		return nil, fmt.Errorf("Error loading key from %s: %v", KeyPath, err)
+	}
+
+	projects, err := loadProjects()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Store{
+		Projects: projects,
+		key:      key,
+	}, nil
+}
+
+func loadProjects() (map[string]map[string]map[string]string, error) {
+	store := &Store{}
+
+	data, err := ioutil.ReadFile(StorePath)
+	if err != nil && os.IsNotExist(err) == false {
+		return nil, err
+	} else if err == nil {
+		err = yaml.Unmarshal(data, store)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing %s: %v", StorePath, err)
+		}
+	}
+
+	if store.Projects == nil {
+		store.Projects = map[string]map[string]map[string]string{}
+	}
+
+	return store.Projects, nil
+}
+
+func loadKey() ([]byte, error) {
+	key, err := ioutil.ReadFile(KeyPath)
+	if err == nil {
+		return key, nil
+	} else if os.IsNotExist(err) == false {
+		return nil, err
+	}
+
+	key = make([]byte, 32)
+	_, err = io.ReadFull(rand.Reader, key)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(filepath.Dir(KeyPath), 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	err = fsutil.WriteFileAtomic(key, KeyPath, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// ProjectID returns the id of the project in the given path within the store
+func ProjectID(basePath string) (string, error) {
+	absPath, err := filepath.Abs(basePath)
+	if err != nil {
+		return "", err
+	}
+
+	return hash.String(absPath), nil
+}
+
+// Get decrypts the value of the secret variable
+func (s *Store) Get(projectID, configName, name string) (string, bool, error) {
+	encrypted, ok := s.Projects[projectID][configName][name]
+	if ok == false {
+		return "", false, nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", false, fmt.Errorf("Error decoding secret %s: %v", name, err)
+	}
+
+	value, err := encryption.DecryptAES(s.key, data)
+	if err != nil {
+		return "", false, fmt.Errorf("Error decrypting secret %s: %v", name, err)
+	}
+
+	return string(value), true, nil
+}
+
+// Set encrypts and sets the value of the secret variable
+func (s *Store) Set(projectID, configName, name, value string) error {
+	encrypted, err := encryption.EncryptAES(s.key, []byte(value))
+	if err != nil {
+		return err
+	}
+
+	c := &change{
+		projectID:  projectID,
+		configName: configName,
+		name:       name,
+		encrypted:  base64.StdEncoding.EncodeToString(encrypted),
+	}
+
+	s.changes = append(s.changes, c)
+	s.apply(c)
+	return nil
+}
+
+func (s *Store) apply(c *change) {
+	if s.Projects[c.projectID] == nil {
+		s.Projects[c.projectID] = map[string]map[string]string{}
+	}
+	if s.Projects[c.projectID][c.configName] == nil {
+		s.Projects[c.projectID][c.configName] = map[string]string{}
+	}
+
+	s.Projects[c.projectID][c.configName][c.name] = c.encrypted
+}
+
+// Save writes the changed values to disk, readable only by the user. Values that other processes
+// saved in the meantime are kept
+func (s *Store) Save() error {
+	lock, err := fsutil.Lock(StorePath)
+	if err != nil {
+		return fmt.Errorf("Error locking %s: %v", StorePath, err)
+	}
+
+	defer lock.Unlock()
+
+	projects, err := loadProjects()
+	if err != nil {
+		return err
+	}
+
+	s.Projects = projects
+	for _, c := range s.changes {
+		s.apply(c)
+	}
+
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	err = fsutil.WriteFileAtomic(data, StorePath, 0600)
+	if err != nil {
+		return err
+	}
+
+	s.changes = nil
+	return nil
+}
@@ -0,0 +1,62 @@
+package secrets
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveKeepsConcurrentChanges(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	oldStorePath, oldKeyPath := StorePath, KeyPath
+	StorePath, KeyPath = filepath.Join(dir, "secrets.yaml"), filepath.Join(dir, "secrets.key")
+	defer func() { StorePath, KeyPath = oldStorePath, oldKeyPath }()
+
+	first, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for store, name := range map[*Store]string{first: "FIRST", second: "SECOND"} {
+		err = store.Set("project", "default", name, "value of "+name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = store.Save()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"FIRST", "SECOND"} {
+		value, ok, err := store.Get("project", "default", name)
+		if err != nil || ok == false || value != "value of "+name {
+			t.Fatalf("Expected %s to be saved, got %q (%v)", name, value, err)
+		}
+	}
+
+	stat, err := os.Stat(StorePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stat.Mode().Perm() != 0600 {
+		t.Fatalf("Expected store to be only readable by the user, got %v", stat.Mode().Perm())
+	}
+}
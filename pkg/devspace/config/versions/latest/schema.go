@@ -32,14 +32,24 @@ func NewRaw() *Config {
 // Config defines the configuration
 type Config struct {
 	Version      *string                  `yaml:"version"`
+	Imports      *[]*ImportConfig         `yaml:"imports,omitempty"`
 	Dependencies *[]*DependencyConfig     `yaml:"dependencies,omitempty"`
 	Images       *map[string]*ImageConfig `yaml:"images,omitempty"`
 	Deployments  *[]*DeploymentConfig     `yaml:"deployments,omitempty"`
 	Hooks        *[]*HookConfig           `yaml:"hooks,omitempty"`
+	Commands     *[]*CommandConfig        `yaml:"commands,omitempty"`
 	Dev          *DevConfig               `yaml:"dev,omitempty"`
 	Cluster      *Cluster                 `yaml:"cluster,omitempty"`
 }
 
+// ImportConfig defines a config partial that is merged into the config before the config itself.
+// The partial is either loaded from a local path or from a path within a git repository
+type ImportConfig struct {
+	Path *string `yaml:"path,omitempty"`
+	Git  *string `yaml:"git,omitempty"`
+	Ref  *string `yaml:"ref,omitempty"`
+}
+
 // HookConfig defines a hook
 type HookConfig struct {
 	Command *string    `yaml:"command"`
@@ -48,6 +58,16 @@ type HookConfig struct {
 	When *HookWhenConfig `yaml:"when,omitempty"`
 }
 
+// CommandConfig defines a custom command that can be executed with devspace run. If a selector is defined,
+// the command is executed within the selected container, otherwise it is executed locally
+type CommandConfig struct {
+	Name          *string `yaml:"name"`
+	Command       *string `yaml:"command"`
+	Description   *string `yaml:"description,omitempty"`
+	Selector      *string `yaml:"selector,omitempty"`
+	ContainerName *string `yaml:"containerName,omitempty"`
+}
+
 // HookWhenConfig defines when the hook should be executed
 type HookWhenConfig struct {
 	Before *HookWhenAtConfig `yaml:"before,omitempty"`
@@ -277,10 +297,14 @@ type Terminal struct {
 
 // PortForwardingConfig defines the ports for a port forwarding to a DevSpace
 type PortForwardingConfig struct {
-	Selector      *string             `yaml:"selector,omitempty"`
-	Namespace     *string             `yaml:"namespace,omitempty"`
-	LabelSelector *map[string]*string `yaml:"labelSelector,omitempty"`
-	PortMappings  *[]*PortMapping     `yaml:"forward"`
+	Selector              *string             `yaml:"selector,omitempty"`
+	Namespace             *string             `yaml:"namespace,omitempty"`
+	LabelSelector         *map[string]*string `yaml:"labelSelector,omitempty"`
+	Service               *string             `yaml:"service,omitempty"`
+	ContainerName         *string             `yaml:"containerName,omitempty"`
+	ForwardContainerPorts *bool               `yaml:"forwardContainerPorts,omitempty"`
+	PortMappings          *[]*PortMapping     `yaml:"forward,omitempty"`
+	ReverseForward        *[]*PortMapping     `yaml:"reverseForward,omitempty"`
 }
 
 // PortMapping defines the ports for a PortMapping
@@ -302,7 +326,25 @@ type SyncConfig struct {
 	WaitInitialSync      *bool               `yaml:"waitInitialSync,omitempty"`
 	DownloadExcludePaths *[]string           `yaml:"downloadExcludePaths,omitempty"`
 	UploadExcludePaths   *[]string           `yaml:"uploadExcludePaths,omitempty"`
+	UseGitIgnore         *bool               `yaml:"useGitIgnore,omitempty"`
+	UseDockerIgnore      *bool               `yaml:"useDockerIgnore,omitempty"`
 	BandwidthLimits      *BandwidthLimits    `yaml:"bandwidthLimits,omitempty"`
+	UseAgent             *bool               `yaml:"useAgent,omitempty"`
+	Checksum             *bool               `yaml:"checksum,omitempty"`
+	ConflictPolicy       *string             `yaml:"conflictPolicy,omitempty"`
+	AllReplicas          *bool               `yaml:"allReplicas,omitempty"`
+	FileMode             *string             `yaml:"fileMode,omitempty"`
+	DirMode              *string             `yaml:"dirMode,omitempty"`
+	UID                  *int                `yaml:"uid,omitempty"`
+	GID                  *int                `yaml:"gid,omitempty"`
+	Compression          *string             `yaml:"compression,omitempty"`
+	OnUpload             *[]*SyncOnUpload    `yaml:"onUpload,omitempty"`
+}
+
+// SyncOnUpload defines a command that is executed in the container after matching files were uploaded
+type SyncOnUpload struct {
+	Paths   *[]string  `yaml:"paths,omitempty"`
+	Command *[]*string `yaml:"command"`
 }
 
 // BandwidthLimits defines the struct for specifying the sync bandwidth limits
@@ -333,6 +375,10 @@ type Cluster struct {
 	APIServer   *string      `yaml:"apiServer,omitempty"`
 	CaCert      *string      `yaml:"caCert,omitempty"`
 	User        *ClusterUser `yaml:"user,omitempty"`
+
+	AllowedContexts   *[]*string `yaml:"allowedContexts,omitempty"`
+	AllowedNamespaces *[]*string `yaml:"allowedNamespaces,omitempty"`
+	Protected         *bool      `yaml:"protected,omitempty"`
 }
 
 // ClusterUser is a user with its username and its client certificate
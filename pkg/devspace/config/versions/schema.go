@@ -0,0 +1,43 @@
+package versions
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/versions/latest"
+	"github.com/devspace-cloud/devspace/pkg/util/jsonschema"
+)
+
+//go:generate go run ../../../../scripts/gen-schema/main.go ../../../../docs/website/static/schemas
+
+// GetVersions returns all supported config versions
+func GetVersions() []string {
+	versions := make([]string, 0, len(versionLoader))
+	for version := range versionLoader {
+		versions = append(versions, version)
+	}
+
+	sort.Strings(versions)
+	return versions
+}
+
+// GetSchema returns the json schema of the given config version
+func GetSchema(version string) (*jsonschema.Schema, error) {
+	versionLoadFunc, ok := versionLoader[version]
+	if ok == false {
+		return nil, fmt.Errorf("Unrecognized config version %s. Please upgrade devspace with `devspace upgrade`", version)
+	}
+
+	schema := jsonschema.Generate(versionLoadFunc(), "DevSpace config "+version)
+	if schema.Properties["version"] != nil {
+		schema.Properties["version"].Enum = []interface{}{version}
+	}
+
+	return schema, nil
+}
+
+// GetLatestSchema returns the json schema of the latest config version
+func GetLatestSchema() *jsonschema.Schema {
+	schema, _ := GetSchema(latest.Version)
+	return schema
+}
@@ -0,0 +1,31 @@
+package versions
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestShippedSchemas(t *testing.T) {
+	for _, version := range GetVersions() {
+		schema, err := GetSchema(version)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected, err := json.MarshalIndent(schema, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		shipped, err := ioutil.ReadFile(filepath.Join("..", "..", "..", "..", "docs", "website", "static", "schemas", version+".json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(append(expected, '\n')) != string(shipped) {
+			t.Fatalf("Schema of version %s is outdated, please run go generate ./pkg/devspace/config/versions", version)
+		}
+	}
+}
@@ -103,17 +103,23 @@ func RemovePort(removeAll bool, labelSelector string, args []string) error {
 
 			newPortMappings := []*latest.PortMapping{}
 
-			for _, pm := range *v.PortMappings {
-				if containsPort(strconv.Itoa(*pm.LocalPort), ports) || containsPort(strconv.Itoa(*pm.RemotePort), ports) {
-					continue
-				}
+			if v.PortMappings != nil {
+				for _, pm := range *v.PortMappings {
+					if containsPort(strconv.Itoa(*pm.LocalPort), ports) || containsPort(strconv.Itoa(*pm.RemotePort), ports) {
+						continue
+					}
 
-				newPortMappings = append(newPortMappings, pm)
+					newPortMappings = append(newPortMappings, pm)
+				}
 			}
 
 			if len(newPortMappings) > 0 {
 				v.PortMappings = &newPortMappings
 				newPortForwards = append(newPortForwards, v)
+			} else if v.ReverseForward != nil {
+				// Keep the reverse forwardings of this port config
+				v.PortMappings = nil
+				newPortForwards = append(newPortForwards, v)
 			}
 		}
 
@@ -154,7 +160,11 @@ func insertOrReplacePortMapping(config *latest.Config, namespace string, labelSe
 		}
 
 		if areLabelMapsEqual(selectors, labelSelectorMap) {
-			portMap := append(*v.PortMappings, portMappings...)
+			portMap := portMappings
+			if v.PortMappings != nil {
+				portMap = append(*v.PortMappings, portMappings...)
+			}
+
 			v.PortMappings = &portMap
 
 			return
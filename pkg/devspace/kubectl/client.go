@@ -47,8 +47,16 @@ func NewClientFromContext(devSpaceConfig *latest.Config, context string) (kubern
 	return kubernetes.NewForConfig(config)
 }
 
-// NewClientWithContextSwitch creates a new kubernetes client and switches the kubectl context
-func NewClientWithContextSwitch(devSpaceConfig *latest.Config, switchContext bool) (kubernetes.Interface, error) {
+// NewClientWithContextSwitch creates a new kubernetes client and switches the kubectl context. Unless
+// skipGuardrails is set, the kube context and namespace have to be allowed by the cluster config
+func NewClientWithContextSwitch(devSpaceConfig *latest.Config, switchContext, skipGuardrails bool) (kubernetes.Interface, error) {
+	if skipGuardrails == false {
+		err := CheckGuardrails(devSpaceConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	config, err := getClientConfig(devSpaceConfig, nil, switchContext)
 	if err != nil {
 		return nil, err
@@ -350,15 +358,27 @@ func GetPodStatus(pod *k8sv1.Pod) string {
 
 // GetPodsFromDeployment retrieves all found pods from a deployment name
 func GetPodsFromDeployment(kubectl kubernetes.Interface, deployment, namespace string) (*k8sv1.PodList, error) {
+	matchLabelString, err := GetDeploymentLabelSelector(kubectl, deployment, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return kubectl.Core().Pods(namespace).List(metav1.ListOptions{
+		LabelSelector: matchLabelString,
+	})
+}
+
+// GetDeploymentLabelSelector returns the matchLabels of the given deployment as label selector string
+func GetDeploymentLabelSelector(kubectl kubernetes.Interface, deployment, namespace string) (string, error) {
 	deploy, err := kubectl.ExtensionsV1beta1().Deployments(namespace).Get(deployment, metav1.GetOptions{})
 	// Deployment not there
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
 	matchLabels := deploy.Spec.Selector.MatchLabels
 	if len(matchLabels) <= 0 {
-		return nil, errors.New("No matchLabels defined deployment")
+		return "", errors.New("No matchLabels defined deployment")
 	}
 
 	matchLabelString := ""
@@ -370,9 +390,7 @@ func GetPodsFromDeployment(kubectl kubernetes.Interface, deployment, namespace s
 		matchLabelString += k + "=" + v
 	}
 
-	return kubectl.Core().Pods(namespace).List(metav1.ListOptions{
-		LabelSelector: matchLabelString,
-	})
+	return matchLabelString, nil
 }
 
 // ForwardPorts forwards the specified ports on the specified interface addresses from the cluster to the local machine
@@ -0,0 +1,96 @@
+package kubectl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/configutil"
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/versions/latest"
+	"github.com/devspace-cloud/devspace/pkg/util/kubeconfig"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// GuardrailsOverrideFlag is the flag that skips the cluster guardrails of the config
+const GuardrailsOverrideFlag = "--i-know-what-im-doing"
+
+// CheckGuardrails checks if the kube context and namespace the config points to are allowed by
+// cluster.allowedContexts, cluster.allowedNamespaces and cluster.protected
+func CheckGuardrails(config *latest.Config) error {
+	if config == nil || config.Cluster == nil {
+		return nil
+	}
+	if config.Cluster.AllowedContexts == nil && config.Cluster.AllowedNamespaces == nil && (config.Cluster.Protected == nil || *config.Cluster.Protected == false) {
+		return nil
+	}
+
+	kubeContext, err := getTargetContext(config)
+	if err != nil {
+		return err
+	}
+
+	namespace, err := configutil.GetDefaultNamespace(config)
+	if err != nil {
+		return err
+	}
+
+	if config.Cluster.Protected != nil && *config.Cluster.Protected {
+		return fmt.Errorf("The cluster is protected (cluster.protected) and this command would change resources in context '%s' and namespace '%s'. If you really want to run it, rerun the command with %s", kubeContext, namespace, GuardrailsOverrideFlag)
+	}
+	if config.Cluster.AllowedContexts != nil && matchesAny(*config.Cluster.AllowedContexts, kubeContext) == false {
+		return fmt.Errorf("Context '%s' is not in the allowed contexts %s (cluster.allowedContexts). Please switch to an allowed context or, if you really want to use this context, rerun the command with %s", kubeContext, joinPatterns(*config.Cluster.AllowedContexts), GuardrailsOverrideFlag)
+	}
+	if config.Cluster.AllowedNamespaces != nil && matchesAny(*config.Cluster.AllowedNamespaces, namespace) == false {
+		return fmt.Errorf("Namespace '%s' is not in the allowed namespaces %s (cluster.allowedNamespaces). Please use an allowed namespace or, if you really want to use this namespace, rerun the command with %s", namespace, joinPatterns(*config.Cluster.AllowedNamespaces), GuardrailsOverrideFlag)
+	}
+
+	return nil
+}
+
+// getTargetContext returns the kube context the config points to or the api server if no kube config is used
+func getTargetContext(config *latest.Config) (string, error) {
+	if config.Cluster.APIServer != nil {
+		return *config.Cluster.APIServer, nil
+	}
+	if config.Cluster.KubeContext != nil && *config.Cluster.KubeContext != "" {
+		return *config.Cluster.KubeContext, nil
+	}
+
+	kubeConfig, err := kubeconfig.ReadKubeConfig(clientcmd.RecommendedHomeFile)
+	if err != nil {
+		return "", err
+	}
+
+	return kubeConfig.CurrentContext, nil
+}
+
+func matchesAny(patterns []*string, value string) bool {
+	for _, pattern := range patterns {
+		if pattern != nil && matchGlob(*pattern, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchGlob matches the value against the pattern where * matches any sequence of characters
+// (including /) and ? matches a single character
+func matchGlob(pattern, value string) bool {
+	expression := regexp.QuoteMeta(pattern)
+	expression = strings.Replace(expression, "\\*", ".*", -1)
+	expression = strings.Replace(expression, "\\?", ".", -1)
+
+	return regexp.MustCompile("^" + expression + "$").MatchString(value)
+}
+
+func joinPatterns(patterns []*string) string {
+	values := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		if pattern != nil {
+			values = append(values, *pattern)
+		}
+	}
+
+	return "[" + strings.Join(values, ", ") + "]"
+}
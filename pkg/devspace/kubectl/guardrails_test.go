@@ -0,0 +1,58 @@
+package kubectl
+
+import (
+	"testing"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/versions/latest"
+	"github.com/devspace-cloud/devspace/pkg/util/ptr"
+)
+
+func TestCheckGuardrails(t *testing.T) {
+	newConfig := func(cluster *latest.Cluster) *latest.Config {
+		cluster.KubeContext = ptr.String("arn:aws:eks:eu-west-1:123:cluster/dev")
+		cluster.Namespace = ptr.String("team-a-dev")
+		return &latest.Config{Cluster: cluster}
+	}
+
+	testCases := map[string]struct {
+		cluster     *latest.Cluster
+		expectError bool
+	}{
+		"no guardrails": {
+			cluster: &latest.Cluster{},
+		},
+		"allowed context and namespace": {
+			cluster: &latest.Cluster{
+				AllowedContexts:   &[]*string{ptr.String("minikube"), ptr.String("arn:aws:eks:*:cluster/dev")},
+				AllowedNamespaces: &[]*string{ptr.String("team-?-*")},
+			},
+		},
+		"context not allowed": {
+			cluster: &latest.Cluster{
+				AllowedContexts: &[]*string{ptr.String("*/staging")},
+			},
+			expectError: true,
+		},
+		"namespace not allowed": {
+			cluster: &latest.Cluster{
+				AllowedNamespaces: &[]*string{ptr.String("team-a")},
+			},
+			expectError: true,
+		},
+		"protected": {
+			cluster: &latest.Cluster{
+				Protected: ptr.Bool(true),
+			},
+			expectError: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		err := CheckGuardrails(newConfig(testCase.cluster))
+		if testCase.expectError && err == nil {
+			t.Fatalf("Expected error in test case %s", name)
+		} else if testCase.expectError == false && err != nil {
+			t.Fatalf("Unexpected error in test case %s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package kubectl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	k8sv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// GetServiceLabelSelector retrieves the given service and returns its pod selector as label selector string
+func GetServiceLabelSelector(client kubernetes.Interface, namespace, name string) (*k8sv1.Service, string, error) {
+	service, err := client.Core().Services(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, "", err
+	}
+	if len(service.Spec.Selector) == 0 {
+		return nil, "", fmt.Errorf("Service %s/%s has no pod selector", namespace, name)
+	}
+
+	labels := make([]string, 0, len(service.Spec.Selector))
+	for key, value := range service.Spec.Selector {
+		labels = append(labels, key+"="+value)
+	}
+
+	sort.Strings(labels)
+	return service, strings.Join(labels, ","), nil
+}
+
+// GetServiceTargetPort returns the port within the given pod the service port is forwarded to
+func GetServiceTargetPort(service *k8sv1.Service, pod *k8sv1.Pod, port int) (int, error) {
+	for _, servicePort := range service.Spec.Ports {
+		if int(servicePort.Port) != port {
+			continue
+		}
+
+		if servicePort.TargetPort.StrVal != "" {
+			for _, container := range pod.Spec.Containers {
+				for _, containerPort := range container.Ports {
+					if containerPort.Name == servicePort.TargetPort.StrVal {
+						return int(containerPort.ContainerPort), nil
+					}
+				}
+			}
+
+			return 0, fmt.Errorf("Couldn't find container port %s of service %s in pod %s", servicePort.TargetPort.StrVal, service.Name, pod.Name)
+		}
+		if servicePort.TargetPort.IntVal != 0 {
+			return int(servicePort.TargetPort.IntVal), nil
+		}
+
+		return port, nil
+	}
+
+	return 0, fmt.Errorf("Service %s has no port %d", service.Name, port)
+}
+
+// GetContainerPorts returns the tcp ports the given container declares
+func GetContainerPorts(container *k8sv1.Container) []int {
+	ports := make([]int, 0, len(container.Ports))
+	for _, containerPort := range container.Ports {
+		if containerPort.Protocol == "" || containerPort.Protocol == k8sv1.ProtocolTCP {
+			ports = append(ports, int(containerPort.ContainerPort))
+		}
+	}
+
+	return ports
+}
@@ -0,0 +1,72 @@
+package kubectl
+
+import (
+	"testing"
+
+	k8sv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestServicePorts(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	_, err := kubeClient.Core().Services("default").Create(&k8sv1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+		Spec: k8sv1.ServiceSpec{
+			Selector: map[string]string{"app": "api", "tier": "backend"},
+			Ports: []k8sv1.ServicePort{
+				{Port: 80, TargetPort: intstr.FromInt(8080)},
+				{Port: 443, TargetPort: intstr.FromString("https")},
+				{Port: 9000},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	service, labelSelector, err := GetServiceLabelSelector(kubeClient, "default", "api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if labelSelector != "app=api,tier=backend" {
+		t.Fatalf("Unexpected label selector %s", labelSelector)
+	}
+
+	pod := &k8sv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-pod"},
+		Spec: k8sv1.PodSpec{
+			Containers: []k8sv1.Container{
+				{
+					Name: "api",
+					Ports: []k8sv1.ContainerPort{
+						{Name: "http", ContainerPort: 8080},
+						{Name: "https", ContainerPort: 8443},
+						{Name: "dns", ContainerPort: 53, Protocol: k8sv1.ProtocolUDP},
+					},
+				},
+			},
+		},
+	}
+
+	for servicePort, expected := range map[int]int{80: 8080, 443: 8443, 9000: 9000} {
+		targetPort, err := GetServiceTargetPort(service, pod, servicePort)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if targetPort != expected {
+			t.Fatalf("Expected service port %d to target %d, got %d", servicePort, expected, targetPort)
+		}
+	}
+
+	_, err = GetServiceTargetPort(service, pod, 81)
+	if err == nil {
+		t.Fatal("Expected error for unknown service port")
+	}
+
+	containerPorts := GetContainerPorts(&pod.Spec.Containers[0])
+	if len(containerPorts) != 2 || containerPorts[0] != 8080 || containerPorts[1] != 8443 {
+		t.Fatalf("Unexpected container ports %v", containerPorts)
+	}
+}
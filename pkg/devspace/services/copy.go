@@ -0,0 +1,54 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/versions/latest"
+	"github.com/devspace-cloud/devspace/pkg/devspace/services/targetselector"
+	"github.com/devspace-cloud/devspace/pkg/devspace/sync"
+	"github.com/devspace-cloud/devspace/pkg/util/log"
+	"k8s.io/client-go/kubernetes"
+)
+
+// CopyFromCmd copies files between the local path and the container path of the selected container. If upload
+// is true, the local file or folder is copied into the container path, otherwise the container file or folder
+// is copied to the local path
+func CopyFromCmd(config *latest.Config, client kubernetes.Interface, cmdParameter targetselector.CmdParameter, localPath, containerPath string, upload bool, excludePaths []string, log log.Logger) error {
+	targetSelector, err := targetselector.NewTargetSelector(config, &targetselector.SelectorParameter{
+		CmdParameter: cmdParameter,
+	}, true)
+	if err != nil {
+		return err
+	}
+
+	pod, container, err := targetSelector.GetContainer(client)
+	if err != nil {
+		return err
+	}
+
+	if containerPath == "" {
+		containerPath = "."
+	}
+
+	if upload {
+		log.StartWait(fmt.Sprintf("Copying %s to %s:%s", localPath, pod.Name, containerPath))
+		err = sync.CopyToContainer(config, client, pod, container, localPath, containerPath, excludePaths)
+		log.StopWait()
+		if err != nil {
+			return fmt.Errorf("Error copying %s to %s:%s: %v", localPath, pod.Name, containerPath, err)
+		}
+
+		log.Donef("Copied %s to %s:%s (Pod: %s/%s)", localPath, pod.Name, containerPath, pod.Namespace, pod.Name)
+		return nil
+	}
+
+	log.StartWait(fmt.Sprintf("Copying %s:%s to %s", pod.Name, containerPath, localPath))
+	err = sync.CopyFromContainer(config, client, pod, container, containerPath, localPath, excludePaths)
+	log.StopWait()
+	if err != nil {
+		return fmt.Errorf("Error copying %s:%s to %s: %v", pod.Name, containerPath, localPath, err)
+	}
+
+	log.Donef("Copied %s:%s to %s (Pod: %s/%s)", pod.Name, containerPath, localPath, pod.Namespace, pod.Name)
+	return nil
+}
@@ -0,0 +1,341 @@
+package services
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/versions/latest"
+	"github.com/devspace-cloud/devspace/pkg/devspace/kubectl"
+	"github.com/devspace-cloud/devspace/pkg/devspace/services/targetselector"
+	"github.com/devspace-cloud/devspace/pkg/util/log"
+	"github.com/mgutz/ansi"
+	k8sv1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// logPrefixColors are the colors the pod/container prefixes of aggregated logs cycle through
+var logPrefixColors = []string{"cyan+b", "green+b", "yellow+b", "magenta+b", "blue+b", "red+b", "cyan", "green", "yellow", "magenta", "blue", "red"}
+
+// maxLogLineSize is the maximum size of a single log line
+const maxLogLineSize = 1024 * 1024
+
+// LogOptions configure the aggregated log streaming of multiple pods and containers
+type LogOptions struct {
+	Follow bool
+	Tail   int64
+	Since  time.Duration
+
+	// Include and Exclude filter the printed log lines
+	Include *regexp.Regexp
+	Exclude *regexp.Regexp
+
+	// JSON prints every line as json object with pod and container fields. Lines that are json objects
+	// themselves are passed through with the additional fields
+	JSON bool
+}
+
+// LogStreamFunc opens the log stream of a container
+type LogStreamFunc func(pod *k8sv1.Pod, options *k8sv1.PodLogOptions) (io.ReadCloser, error)
+
+// StartMultiLogs streams the logs of all containers of all running pods matching the selector or deployment
+// concurrently and prefixes every line with pod/container
+func StartMultiLogs(config *latest.Config, client kubernetes.Interface, cmdParameter targetselector.CmdParameter, deployment string, options *LogOptions, log log.Logger) error {
+	return StartMultiLogsWithWriter(config, client, cmdParameter, deployment, options, log, os.Stdout)
+}
+
+// StartMultiLogsWithWriter streams the aggregated logs to the given writer. If options.Follow is true, pods that
+// start later are picked up and the function does not return
+func StartMultiLogsWithWriter(config *latest.Config, client kubernetes.Interface, cmdParameter targetselector.CmdParameter, deployment string, options *LogOptions, log log.Logger, stdout io.Writer) error {
+	selectorParameter := &targetselector.SelectorParameter{
+		CmdParameter: cmdParameter,
+	}
+
+	if config != nil && config.Dev != nil && config.Dev.Terminal != nil {
+		selectorParameter.ConfigParameter = targetselector.ConfigParameter{
+			Selector:      config.Dev.Terminal.Selector,
+			Namespace:     config.Dev.Terminal.Namespace,
+			LabelSelector: config.Dev.Terminal.LabelSelector,
+			ContainerName: config.Dev.Terminal.ContainerName,
+		}
+	}
+
+	if deployment != "" {
+		namespace, err := selectorParameter.GetNamespace(config)
+		if err != nil {
+			return err
+		}
+
+		labelSelector, err := kubectl.GetDeploymentLabelSelector(client, deployment, namespace)
+		if err != nil {
+			return fmt.Errorf("Error resolving deployment %s: %v", deployment, err)
+		}
+
+		selectorParameter.CmdParameter.Namespace = &namespace
+		selectorParameter.CmdParameter.LabelSelector = &labelSelector
+	}
+
+	targetSelector, err := targetselector.NewTargetSelector(config, selectorParameter, false)
+	if err != nil {
+		return err
+	}
+
+	m := newMultiLogs(func() ([]*k8sv1.Pod, error) {
+		return targetSelector.GetRunningPods(client)
+	}, func(pod *k8sv1.Pod, logOptions *k8sv1.PodLogOptions) (io.ReadCloser, error) {
+		return client.Core().Pods(pod.Namespace).GetLogs(pod.Name, logOptions).Stream()
+	}, selectorParameter.GetContainerName(), options, stdout, log)
+
+	return m.run()
+}
+
+// StartFilteredLogs streams the logs of the single selected pod container with the given filters. The pod
+// is selected like in StartLogs, so the filters don't change which pod is printed
+func StartFilteredLogs(config *latest.Config, client kubernetes.Interface, cmdParameter targetselector.CmdParameter, options *LogOptions, log log.Logger) error {
+	selectorParameter := &targetselector.SelectorParameter{
+		CmdParameter: cmdParameter,
+	}
+
+	if config != nil && config.Dev != nil && config.Dev.Terminal != nil {
+		selectorParameter.ConfigParameter = targetselector.ConfigParameter{
+			Selector:      config.Dev.Terminal.Selector,
+			Namespace:     config.Dev.Terminal.Namespace,
+			LabelSelector: config.Dev.Terminal.LabelSelector,
+			ContainerName: config.Dev.Terminal.ContainerName,
+		}
+	}
+
+	targetSelector, err := targetselector.NewTargetSelector(config, selectorParameter, true)
+	if err != nil {
+		return err
+	}
+
+	pod, container, err := targetSelector.GetContainer(client)
+	if err != nil {
+		return err
+	}
+
+	m := newMultiLogs(getPodFunc(client, pod), func(pod *k8sv1.Pod, logOptions *k8sv1.PodLogOptions) (io.ReadCloser, error) {
+		return client.Core().Pods(pod.Namespace).GetLogs(pod.Name, logOptions).Stream()
+	}, &container.Name, options, os.Stdout, log)
+
+	return m.run()
+}
+
+// getPodFunc lists only the given pod as long as it exists
+func getPodFunc(client kubernetes.Interface, pod *k8sv1.Pod) ListFunc {
+	return func() ([]*k8sv1.Pod, error) {
+		current, err := client.Core().Pods(pod.Namespace).Get(pod.Name, metav1.GetOptions{})
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				return []*k8sv1.Pod{}, nil
+			}
+
+			return nil, err
+		}
+
+		return []*k8sv1.Pod{current}, nil
+	}
+}
+
+// multiLogs follows the logs of every container of every listed pod
+type multiLogs struct {
+	listPods      ListFunc
+	openStream    LogStreamFunc
+	containerName *string
+	options       *LogOptions
+	log           log.Logger
+	interval      time.Duration
+
+	out      io.Writer
+	outMutex sync.Mutex
+
+	// streams holds the currently streamed containers, ended holds the time the stream of a container ended
+	streams map[string]bool
+	ended   map[string]metav1.Time
+	colors  map[string]string
+	mutex   sync.Mutex
+	wg      sync.WaitGroup
+
+	interrupt chan bool
+}
+
+func newMultiLogs(listPods ListFunc, openStream LogStreamFunc, containerName *string, options *LogOptions, out io.Writer, log log.Logger) *multiLogs {
+	return &multiLogs{
+		listPods:      listPods,
+		openStream:    openStream,
+		containerName: containerName,
+		options:       options,
+		log:           log,
+		interval:      supervisorInterval,
+		out:           out,
+		streams:       make(map[string]bool),
+		ended:         make(map[string]metav1.Time),
+		colors:        make(map[string]string),
+		interrupt:     make(chan bool),
+	}
+}
+
+func (m *multiLogs) run() error {
+	pods, err := m.listPods()
+	if err != nil {
+		return err
+	} else if len(pods) == 0 && m.options.Follow == false {
+		return fmt.Errorf("Couldn't find a running pod")
+	}
+
+	m.update(pods)
+	if m.options.Follow == false {
+		m.wg.Wait()
+		return nil
+	}
+
+	for {
+		select {
+		case <-m.interrupt:
+			return nil
+		case <-time.After(m.interval):
+		}
+
+		pods, err := m.listPods()
+		if err != nil {
+			m.log.Warnf("Unable to list pods: %v (retrying)", err)
+			continue
+		}
+
+		m.update(pods)
+	}
+}
+
+// update starts streaming the logs of all containers that are not streamed yet
+func (m *multiLogs) update(pods []*k8sv1.Pod) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, pod := range pods {
+		for _, container := range pod.Spec.Containers {
+			if m.containerName != nil && container.Name != *m.containerName {
+				continue
+			}
+
+			key := string(pod.UID) + "/" + pod.Name + "/" + container.Name
+			if m.streams[key] || hasStarted(pod, container.Name) == false {
+				continue
+			}
+
+			logOptions := &k8sv1.PodLogOptions{
+				Container: container.Name,
+				Follow:    m.options.Follow,
+			}
+
+			if endTime, ok := m.ended[key]; ok {
+				// The container was restarted, so we only print the new lines
+				logOptions.SinceTime = &endTime
+			} else {
+				if m.options.Tail >= 0 {
+					logOptions.TailLines = &m.options.Tail
+				}
+				if m.options.Since > 0 {
+					sinceSeconds := int64(m.options.Since.Seconds())
+					logOptions.SinceSeconds = &sinceSeconds
+				}
+			}
+
+			prefix := pod.Name + "/" + container.Name
+			if m.colors[prefix] == "" {
+				m.colors[prefix] = logPrefixColors[len(m.colors)%len(logPrefixColors)]
+			}
+
+			m.streams[key] = true
+			m.wg.Add(1)
+
+			go m.stream(pod, container.Name, key, m.colors[prefix], logOptions)
+		}
+	}
+}
+
+// hasStarted returns true if the container is running or has terminated, waiting containers have no logs yet
+func hasStarted(pod *k8sv1.Pod, containerName string) bool {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == containerName {
+			return status.State.Running != nil || status.State.Terminated != nil
+		}
+	}
+
+	return false
+}
+
+func (m *multiLogs) stream(pod *k8sv1.Pod, containerName, key, color string, logOptions *k8sv1.PodLogOptions) {
+	defer m.wg.Done()
+	defer func() {
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+
+		delete(m.streams, key)
+		m.ended[key] = metav1.Now()
+	}()
+
+	reader, err := m.openStream(pod, logOptions)
+	if err != nil {
+		m.log.Warnf("Unable to stream logs of %s/%s: %v", pod.Name, containerName, err)
+		return
+	}
+
+	defer reader.Close()
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLogLineSize)
+
+	for scanner.Scan() {
+		m.printLine(pod.Name, containerName, color, scanner.Text())
+	}
+}
+
+func (m *multiLogs) printLine(podName, containerName, color, line string) {
+	if m.options.Include != nil && m.options.Include.MatchString(line) == false {
+		return
+	}
+	if m.options.Exclude != nil && m.options.Exclude.MatchString(line) {
+		return
+	}
+
+	var output string
+	if m.options.JSON {
+		output = formatJSONLogLine(podName, containerName, line)
+	} else {
+		output = ansi.Color("["+podName+"/"+containerName+"]", color) + " " + line
+	}
+
+	m.outMutex.Lock()
+	defer m.outMutex.Unlock()
+
+	m.out.Write([]byte(output + "\n"))
+}
+
+// formatJSONLogLine adds the pod and container fields to lines that are json objects and wraps all other lines
+func formatJSONLogLine(podName, containerName, line string) string {
+	podJSON, _ := json.Marshal(podName)
+	containerJSON, _ := json.Marshal(containerName)
+	fields := `"pod":` + string(podJSON) + `,"container":` + string(containerJSON)
+
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, "{") && json.Valid([]byte(trimmed)) {
+		rest := strings.TrimSpace(trimmed[1:])
+		if rest == "}" {
+			return "{" + fields + "}"
+		}
+
+		return "{" + fields + "," + rest
+	}
+
+	messageJSON, _ := json.Marshal(line)
+	return "{" + fields + `,"message":` + string(messageJSON) + "}"
+}
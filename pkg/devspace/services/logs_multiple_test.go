@@ -0,0 +1,121 @@
+package services
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/devspace-cloud/devspace/pkg/util/log"
+
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestMultiLogs(t *testing.T) {
+	pods := []*k8sv1.Pod{createReplica("pod-a"), createReplica("pod-b")}
+
+	var mutex sync.Mutex
+	opened := []string{}
+	openStream := func(pod *k8sv1.Pod, options *k8sv1.PodLogOptions) (io.ReadCloser, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		opened = append(opened, pod.Name+"/"+options.Container)
+		return ioutil.NopCloser(strings.NewReader("ERROR from " + options.Container + "\ninfo\n{\"level\":\"error\"}\n")), nil
+	}
+
+	out := &bytes.Buffer{}
+	m := newMultiLogs(func() ([]*k8sv1.Pod, error) {
+		return pods, nil
+	}, openStream, nil, &LogOptions{Tail: 10, Include: regexp.MustCompile("(?i)error"), Exclude: regexp.MustCompile("sidecar")}, out, &log.DiscardLogger{})
+
+	err := m.run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(opened)
+	if strings.Join(opened, ",") != "pod-a/app,pod-a/sidecar,pod-b/app,pod-b/sidecar" {
+		t.Fatalf("Unexpected streams %v", opened)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	sort.Strings(lines)
+	if len(lines) != 6 {
+		t.Fatalf("Expected 6 lines, got %v", lines)
+	}
+	for _, line := range lines {
+		if strings.Contains(line, "info") || strings.Contains(line, "sidecar]") && strings.Contains(line, "ERROR") {
+			t.Fatalf("Unexpected line %s", line)
+		}
+	}
+
+	// New pods are picked up, already streamed containers are not streamed again
+	opened = []string{}
+	m.options.Follow = true
+	m.streams["pod-a/pod-a/app"] = true
+	m.update([]*k8sv1.Pod{pods[0], createReplica("pod-c")})
+	m.wg.Wait()
+
+	sort.Strings(opened)
+	if strings.Join(opened, ",") != "pod-a/sidecar,pod-c/app,pod-c/sidecar" {
+		t.Fatalf("Unexpected streams %v", opened)
+	}
+
+	// Containers that are still waiting are not streamed until they started
+	opened = []string{}
+	waiting := createReplica("pod-d")
+	waiting.Status.ContainerStatuses[1].State = k8sv1.ContainerState{Waiting: &k8sv1.ContainerStateWaiting{Reason: "ContainerCreating"}}
+	m.update([]*k8sv1.Pod{waiting})
+	m.wg.Wait()
+
+	if strings.Join(opened, ",") != "pod-d/app" {
+		t.Fatalf("Unexpected streams %v", opened)
+	}
+}
+
+func TestFormatJSONLogLine(t *testing.T) {
+	tests := map[string]string{
+		"plain text":        `{"pod":"pod-a","container":"app","message":"plain text"}`,
+		`{"level":"info"}`:  `{"pod":"pod-a","container":"app","level":"info"}`,
+		`{}`:                `{"pod":"pod-a","container":"app"}`,
+		`{"broken":`:        `{"pod":"pod-a","container":"app","message":"{\"broken\":"}`,
+		`["not", "object"]`: `{"pod":"pod-a","container":"app","message":"[\"not\", \"object\"]"}`,
+	}
+
+	for line, expected := range tests {
+		formatted := formatJSONLogLine("pod-a", "app", line)
+		if formatted != expected {
+			t.Fatalf("Expected %s for %s, got %s", expected, line, formatted)
+		}
+	}
+}
+
+func TestGetPodFunc(t *testing.T) {
+	pod := createReplica("pod-a")
+	client := fake.NewSimpleClientset(pod, createReplica("pod-b"))
+
+	listPods := getPodFunc(client, pod)
+	pods, err := listPods()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pods) != 1 || pods[0].Name != "pod-a" {
+		t.Fatalf("Expected only pod-a, got %v", pods)
+	}
+
+	err = client.Core().Pods(pod.Namespace).Delete(pod.Name, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pods, err = listPods()
+	if err != nil || len(pods) != 0 {
+		t.Fatalf("Expected no pods after pod-a was deleted, got %v (%v)", pods, err)
+	}
+}
@@ -6,88 +6,260 @@ import (
 	"strings"
 	"time"
 
+	k8sv1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/tools/portforward"
 
 	"github.com/devspace-cloud/devspace/pkg/devspace/config/versions/latest"
 	"github.com/devspace-cloud/devspace/pkg/devspace/kubectl"
 	"github.com/devspace-cloud/devspace/pkg/devspace/services/targetselector"
 	"github.com/devspace-cloud/devspace/pkg/util/log"
+	"github.com/devspace-cloud/devspace/pkg/util/portutil"
 )
 
-// StartPortForwarding starts the port forwarding functionality
-func StartPortForwarding(config *latest.Config, client kubernetes.Interface, log log.Logger) ([]*portforward.PortForwarder, error) {
+// portForwardingStartTimeout is the time we wait for the port forwarding to be ready
+const portForwardingStartTimeout = time.Second * 20
+
+// forwardedPort is a single resolved port mapping of a port forwarding
+type forwardedPort struct {
+	localPort   int
+	remotePort  int
+	bindAddress string
+}
+
+// StartPortForwarding starts the port forwarding functionality. Each port forwarding is supervised and
+// restarted if the target pod is replaced
+func StartPortForwarding(config *latest.Config, client kubernetes.Interface, log log.Logger) ([]*Supervisor, error) {
 	if config.Dev.Ports != nil {
-		portforwarder := make([]*portforward.PortForwarder, 0, len(*config.Dev.Ports))
+		supervisors := make([]*Supervisor, 0, len(*config.Dev.Ports))
 
 		for portConfigIndex, portForwarding := range *config.Dev.Ports {
-			selector, err := targetselector.NewTargetSelector(config, &targetselector.SelectorParameter{
+			selectorParameter := &targetselector.SelectorParameter{
 				ConfigParameter: targetselector.ConfigParameter{
 					Selector:      portForwarding.Selector,
 					Namespace:     portForwarding.Namespace,
 					LabelSelector: portForwarding.LabelSelector,
+					ContainerName: portForwarding.ContainerName,
 				},
-			}, false)
-			if err != nil {
-				return nil, fmt.Errorf("Error creating target selector: %v", err)
 			}
 
-			log.StartWait("Port-Forwarding: Waiting for pods...")
-			pod, err := selector.GetPod(client)
-			log.StopWait()
-			if err != nil {
-				return nil, fmt.Errorf("Error starting port-forwarding: Unable to list devspace pods: %s", err.Error())
-			} else if pod != nil {
-				ports := make([]string, len(*portForwarding.PortMappings))
-				addresses := make([]string, len(*portForwarding.PortMappings))
-
-				for index, value := range *portForwarding.PortMappings {
-					if value.LocalPort == nil {
-						return nil, fmt.Errorf("port is not defined in portmapping %d:%d", portConfigIndex, index)
-					}
+			// Select the pods of the service
+			var service *k8sv1.Service
+			if portForwarding.Service != nil {
+				namespace, err := selectorParameter.GetNamespace(config)
+				if err != nil {
+					return nil, fmt.Errorf("Error resolving namespace: %v", err)
+				}
 
-					localPort := strconv.Itoa(*value.LocalPort)
-					remotePort := localPort
-					if value.RemotePort != nil {
-						remotePort = strconv.Itoa(*value.RemotePort)
-					}
+				var labelSelector string
+				service, labelSelector, err = kubectl.GetServiceLabelSelector(client, namespace, *portForwarding.Service)
+				if err != nil {
+					return nil, fmt.Errorf("Error resolving service %s: %v", *portForwarding.Service, err)
+				}
 
-					ports[index] = localPort + ":" + remotePort
-					if value.BindAddress == nil {
-						addresses[index] = "127.0.0.1"
-					} else {
-						addresses[index] = *value.BindAddress
-					}
+				selectorParameter.CmdParameter.Namespace = &namespace
+				selectorParameter.CmdParameter.LabelSelector = &labelSelector
+			}
+
+			selector, err := targetselector.NewTargetSelector(config, selectorParameter, false)
+			if err != nil {
+				return nil, fmt.Errorf("Error creating target selector: %v", err)
+			}
+
+			forwardContainerPorts := portForwarding.ForwardContainerPorts != nil && *portForwarding.ForwardContainerPorts
+			if (portForwarding.PortMappings != nil && len(*portForwarding.PortMappings) > 0) || forwardContainerPorts {
+				supervisor, err := startPortForwarder(config, client, selector, service, portConfigIndex, portForwarding, log)
+				if err != nil {
+					return nil, err
 				}
 
-				readyChan := make(chan struct{})
+				supervisors = append(supervisors, supervisor)
+			}
 
-				pf, err := kubectl.NewPortForwarder(config, client, pod, ports, addresses, make(chan struct{}), readyChan)
+			if portForwarding.ReverseForward != nil {
+				supervisor, err := startReverseForwarding(config, client, selector, portConfigIndex, *portForwarding.ReverseForward, log)
 				if err != nil {
-					log.Fatalf("Error starting port forwarding: %v", err)
+					return nil, err
 				}
 
-				go func() {
-					err := pf.ForwardPorts()
+				supervisors = append(supervisors, supervisor)
+			}
+		}
+
+		return supervisors, nil
+	}
+
+	return nil, nil
+}
+
+// startPortForwarder starts a supervised port forwarding from the local machine to the selected pod
+func startPortForwarder(config *latest.Config, client kubernetes.Interface, selector *targetselector.TargetSelector, service *k8sv1.Service, portConfigIndex int, portForwarding *latest.PortForwardingConfig, log log.Logger) (*Supervisor, error) {
+	portMappings := []*latest.PortMapping{}
+	if portForwarding.PortMappings != nil {
+		portMappings = *portForwarding.PortMappings
+	}
+
+	names := make([]string, 0, len(portMappings)+1)
+	for index, value := range portMappings {
+		if value.LocalPort == nil {
+			return nil, fmt.Errorf("port is not defined in portmapping %d:%d", portConfigIndex, index)
+		}
+
+		names = append(names, strconv.Itoa(*value.LocalPort))
+	}
+
+	forwardContainerPorts := portForwarding.ForwardContainerPorts != nil && *portForwarding.ForwardContainerPorts
+	if forwardContainerPorts {
+		names = append(names, "container ports")
+	}
+
+	// Remember the local ports we chose instead of occupied ones, so that reconnects use the same ports
+	chosenPorts := map[int]int{}
+
+	var supervisor *Supervisor
+	supervisor = NewSupervisor("port forwarding "+strings.Join(names, ", "), client, selector.GetLabelSelector(), func() (*k8sv1.Pod, *k8sv1.Container, error) {
+		if forwardContainerPorts {
+			pod, container, err := selector.GetContainer(client)
+			if err != nil {
+				return nil, nil, fmt.Errorf("Unable to select container: %v", err)
+			}
+
+			return pod, container, nil
+		}
+
+		pod, err := selector.GetPod(client)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Unable to list devspace pods: %v", err)
+		} else if pod == nil {
+			return nil, nil, fmt.Errorf("Couldn't find a running pod")
+		}
+
+		return pod, nil, nil
+	}, func(pod *k8sv1.Pod, container *k8sv1.Container) (func(), error) {
+		forwardedPorts, err := resolvePortMappings(pod, container, service, portMappings)
+		if err != nil {
+			return nil, err
+		}
+
+		ports := make([]string, len(forwardedPorts))
+		addresses := make([]string, len(forwardedPorts))
+		for index, forwardedPort := range forwardedPorts {
+			if portutil.IsAvailable(forwardedPort.bindAddress, forwardedPort.localPort) == false {
+				localPort, ok := chosenPorts[forwardedPort.localPort]
+				if ok == false || portutil.IsAvailable(forwardedPort.bindAddress, localPort) == false {
+					localPort, err = portutil.GetFreePort(forwardedPort.bindAddress)
 					if err != nil {
-						log.Errorf("Error forwarding ports: %v", err)
+						return nil, fmt.Errorf("Error finding a free local port: %v", err)
 					}
-				}()
+				}
 
-				// Wait till forwarding is ready
-				select {
-				case <-readyChan:
-					log.Donef("Port forwarding started on %s", strings.Join(ports, ", "))
+				log.Warnf("Local port %d is already in use, forwarding local port %d to %d instead", forwardedPort.localPort, localPort, forwardedPort.remotePort)
+				chosenPorts[forwardedPort.localPort] = localPort
+				forwardedPort.localPort = localPort
+			}
 
-					portforwarder = append(portforwarder, pf)
-				case <-time.After(20 * time.Second):
-					return nil, fmt.Errorf("Timeout waiting for port forwarding to start")
-				}
+			ports[index] = strconv.Itoa(forwardedPort.localPort) + ":" + strconv.Itoa(forwardedPort.remotePort)
+			addresses[index] = forwardedPort.bindAddress
+		}
+
+		if len(ports) == 0 {
+			if container == nil {
+				log.Warnf("Pod %s/%s has no ports to forward", pod.Namespace, pod.Name)
+			} else {
+				log.Warnf("Container %s in pod %s/%s declares no ports to forward", container.Name, pod.Namespace, pod.Name)
+			}
+
+			return func() {}, nil
+		}
+
+		readyChan := make(chan struct{})
+		errorChan := make(chan error, 1)
+
+		pf, err := kubectl.NewPortForwarder(config, client, pod, ports, addresses, make(chan struct{}), readyChan)
+		if err != nil {
+			return nil, fmt.Errorf("Error starting port forwarding: %v", err)
+		}
+
+		go func() {
+			err := pf.ForwardPorts()
+			errorChan <- err
+			if err != nil && supervisor.waitForReplacement(pod) == false {
+				log.Errorf("Error forwarding ports: %v", err)
+			}
+		}()
+
+		// Wait till forwarding is ready
+		select {
+		case <-readyChan:
+			log.Donef("Port forwarding started on %s (Pod: %s/%s)", strings.Join(ports, ", "), pod.Namespace, pod.Name)
+		case err := <-errorChan:
+			pf.Close()
+			return nil, fmt.Errorf("Error starting port forwarding on %s: %v", strings.Join(ports, ", "), err)
+		case <-time.After(portForwardingStartTimeout):
+			pf.Close()
+			return nil, fmt.Errorf("Timeout waiting for port forwarding to start")
+		}
+
+		return pf.Close, nil
+	}, log)
+
+	log.StartWait("Port-Forwarding: Waiting for pods...")
+	err := supervisor.Start()
+	log.StopWait()
+	if err != nil {
+		return nil, fmt.Errorf("Error starting port-forwarding: %v", err)
+	}
+
+	return supervisor, nil
+}
+
+// resolvePortMappings returns the ports that should be forwarded to the given pod. Remote ports of a service
+// are translated into the target ports of the pod and, if a container is given, all of its container ports are added
+func resolvePortMappings(pod *k8sv1.Pod, container *k8sv1.Container, service *k8sv1.Service, portMappings []*latest.PortMapping) ([]*forwardedPort, error) {
+	forwardedPorts := make([]*forwardedPort, 0, len(portMappings))
+	remotePorts := map[int]bool{}
+
+	for _, value := range portMappings {
+		remotePort := *value.LocalPort
+		if value.RemotePort != nil {
+			remotePort = *value.RemotePort
+		}
+
+		if service != nil {
+			targetPort, err := kubectl.GetServiceTargetPort(service, pod, remotePort)
+			if err != nil {
+				return nil, err
 			}
+
+			remotePort = targetPort
 		}
 
-		return portforwarder, nil
+		bindAddress := "127.0.0.1"
+		if value.BindAddress != nil {
+			bindAddress = *value.BindAddress
+		}
+
+		remotePorts[remotePort] = true
+		forwardedPorts = append(forwardedPorts, &forwardedPort{
+			localPort:   *value.LocalPort,
+			remotePort:  remotePort,
+			bindAddress: bindAddress,
+		})
 	}
 
-	return nil, nil
+	if container != nil {
+		for _, containerPort := range kubectl.GetContainerPorts(container) {
+			if remotePorts[containerPort] {
+				continue
+			}
+
+			remotePorts[containerPort] = true
+			forwardedPorts = append(forwardedPorts, &forwardedPort{
+				localPort:   containerPort,
+				remotePort:  containerPort,
+				bindAddress: "127.0.0.1",
+			})
+		}
+	}
+
+	return forwardedPorts, nil
 }
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/versions/latest"
+	"github.com/devspace-cloud/devspace/pkg/devspace/kubectl"
+	"github.com/devspace-cloud/devspace/pkg/devspace/services/targetselector"
+	"github.com/devspace-cloud/devspace/pkg/devspace/sync"
+	"github.com/devspace-cloud/devspace/pkg/devspace/sync/agent"
+	"github.com/devspace-cloud/devspace/pkg/util/log"
+)
+
+// reverseTunnelStartTimeout is the time we wait for the agent to listen within the container
+const reverseTunnelStartTimeout = time.Second * 20
+
+// reversePortMapping is a local port that is exposed on the remote address within the container
+type reversePortMapping struct {
+	localPort     string
+	remoteAddress string
+}
+
+// startReverseForwarding starts a supervised reverse port forwarding that makes local ports reachable
+// within the selected container. The connections are tunneled over an exec stream to the sync agent,
+// which listens on the remote port within the container
+func startReverseForwarding(config *latest.Config, client kubernetes.Interface, selector *targetselector.TargetSelector, portConfigIndex int, portMappings []*latest.PortMapping, log log.Logger) (*Supervisor, error) {
+	ports := make([]string, len(portMappings))
+	mappings := make([]*reversePortMapping, len(portMappings))
+
+	for index, value := range portMappings {
+		if value.LocalPort == nil {
+			return nil, fmt.Errorf("port is not defined in reverseForward %d:%d", portConfigIndex, index)
+		}
+
+		localPort := strconv.Itoa(*value.LocalPort)
+		remotePort := localPort
+		if value.RemotePort != nil {
+			remotePort = strconv.Itoa(*value.RemotePort)
+		}
+
+		bindAddress := "127.0.0.1"
+		if value.BindAddress != nil {
+			bindAddress = *value.BindAddress
+		}
+
+		ports[index] = localPort + ":" + remotePort
+		mappings[index] = &reversePortMapping{
+			localPort:     localPort,
+			remoteAddress: net.JoinHostPort(bindAddress, remotePort),
+		}
+	}
+
+	var supervisor *Supervisor
+	supervisor = NewSupervisor("reverse port forwarding "+strings.Join(ports, ", "), client, selector.GetLabelSelector(), func() (*k8sv1.Pod, *k8sv1.Container, error) {
+		pod, container, err := selector.GetContainer(client)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Unable to select container: %v", err)
+		}
+
+		return pod, container, nil
+	}, func(pod *k8sv1.Pod, container *k8sv1.Container) (func(), error) {
+		remotePath, err := sync.InjectAgent(config, client, pod, container.Name, log.Debugf)
+		if err != nil {
+			return nil, fmt.Errorf("Error injecting agent: %v", err)
+		}
+
+		stopFuncs := make([]func(), 0, len(mappings))
+		stopAll := func() {
+			for _, stop := range stopFuncs {
+				stop()
+			}
+		}
+
+		for _, mapping := range mappings {
+			mapping := mapping
+			stop, err := startReverseTunnel(config, client, pod, container.Name, remotePath, mapping, func(err error) {
+				if supervisor.waitForReplacement(pod) == false {
+					log.Errorf("Error forwarding reverse port %s: %v", mapping.localPort, err)
+				}
+			})
+			if err != nil {
+				stopAll()
+				return nil, err
+			}
+
+			stopFuncs = append(stopFuncs, stop)
+		}
+
+		log.Donef("Reverse port forwarding started on %s (Pod: %s/%s)", strings.Join(ports, ", "), pod.Namespace, pod.Name)
+		return stopAll, nil
+	}, log)
+
+	log.StartWait("Reverse-Port-Forwarding: Waiting for pods...")
+	err := supervisor.Start()
+	log.StopWait()
+	if err != nil {
+		return nil, fmt.Errorf("Error starting reverse port-forwarding: %v", err)
+	}
+
+	return supervisor, nil
+}
+
+// startReverseTunnel starts the agent listening on the remote address and forwards all connections
+// to the local port. onStop is called as soon as the tunnel stops
+func startReverseTunnel(config *latest.Config, client kubernetes.Interface, pod *k8sv1.Pod, container string, remotePath string, mapping *reversePortMapping, onStop func(err error)) (func(), error) {
+	stdinReader, stdinWriter, _ := os.Pipe()
+	stdoutReader, stdoutWriter, _ := os.Pipe()
+	errorChan := make(chan error, 1)
+
+	go func() {
+		errorChan <- kubectl.ExecStream(config, client, pod, container, []string{remotePath, "--reverse-forward", mapping.remoteAddress}, false, stdinReader, stdoutWriter, nil)
+		stdoutWriter.Close()
+	}()
+
+	stdout := bufio.NewReader(stdoutReader)
+	readyChan := make(chan error, 1)
+	go func() {
+		line, err := stdout.ReadString('\n')
+		if err == nil && strings.TrimSpace(line) != agent.ReadyAck {
+			err = fmt.Errorf("Unexpected agent output: %s", line)
+		}
+
+		readyChan <- err
+	}()
+
+	select {
+	case err := <-errorChan:
+		if err == nil {
+			err = fmt.Errorf("agent exited unexpectedly")
+		}
+
+		stdinWriter.Close()
+		return nil, fmt.Errorf("Error listening on %s within the container: %v", mapping.remoteAddress, err)
+	case err := <-readyChan:
+		if err != nil {
+			stdinWriter.Close()
+			return nil, fmt.Errorf("Error listening on %s within the container: %v", mapping.remoteAddress, err)
+		}
+	case <-time.After(reverseTunnelStartTimeout):
+		stdinWriter.Close()
+		return nil, fmt.Errorf("Timeout waiting for reverse port forwarding to start")
+	}
+
+	go func() {
+		err := agent.ForwardTunnel(stdout, stdinWriter, func() (net.Conn, error) {
+			return net.Dial("tcp", net.JoinHostPort("127.0.0.1", mapping.localPort))
+		})
+		if err == nil {
+			err = fmt.Errorf("connection to container lost")
+		}
+
+		onStop(err)
+	}()
+
+	return func() {
+		stdinWriter.Close()
+	}, nil
+}
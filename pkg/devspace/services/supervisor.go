@@ -0,0 +1,287 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/kubectl"
+	"github.com/devspace-cloud/devspace/pkg/util/log"
+	k8sv1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// supervisorInterval is the interval in which the supervisor checks if the pod was replaced
+var supervisorInterval = time.Second * 2
+
+// replacementTimeout is the time a service waits for the supervisor to detect a pod replacement after its connection broke
+var replacementTimeout = time.Second * 15
+
+// replacementIgnoredLabels are pod labels that differ between a pod and its replacement and are therefore
+// not used to find the replacement of a pod that was selected without a label selector
+var replacementIgnoredLabels = map[string]bool{
+	"pod-template-hash":                  true,
+	"controller-revision-hash":           true,
+	"statefulset.kubernetes.io/pod-name": true,
+}
+
+// SelectFunc selects the pod and container a service should connect to
+type SelectFunc func() (*k8sv1.Pod, *k8sv1.Container, error)
+
+// ConnectFunc connects a service to the given pod and returns a function that stops the service again
+type ConnectFunc func(pod *k8sv1.Pod, container *k8sv1.Container) (func(), error)
+
+// Supervisor watches the pod a service (sync, port-forwarding, terminal) is connected to and
+// reconnects the service to the newest running pod as soon as the pod is replaced or restarted.
+// Only the initial selection may ask the user, reconnects select the newest running pod that matches
+// the label selector (or the labels of the initially selected pod) without asking
+type Supervisor struct {
+	name          string
+	client        kubernetes.Interface
+	labelSelector *string
+	selectTarget  SelectFunc
+	connect       ConnectFunc
+	log           log.Logger
+	interval      time.Duration
+
+	pod         *k8sv1.Pod
+	target      *k8sv1.Pod
+	container   *k8sv1.Container
+	stopService func()
+	stopped     bool
+	mutex       sync.Mutex
+
+	interrupt chan bool
+	stopOnce  sync.Once
+}
+
+// NewSupervisor creates a new supervisor for the service with the given name. The label selector is used to
+// find the replacement of the selected pod and may be nil
+func NewSupervisor(name string, client kubernetes.Interface, labelSelector *string, selectTarget SelectFunc, connect ConnectFunc, log log.Logger) *Supervisor {
+	return &Supervisor{
+		name:          name,
+		client:        client,
+		labelSelector: labelSelector,
+		selectTarget:  selectTarget,
+		connect:       connect,
+		log:           log,
+		interval:      supervisorInterval,
+		interrupt:     make(chan bool),
+	}
+}
+
+// Start selects the target pod, connects the service and starts watching the pod
+func (s *Supervisor) Start() error {
+	pod, container, err := s.selectTarget()
+	if err != nil {
+		return err
+	}
+
+	stopService, err := s.connect(pod, container)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	s.pod = pod
+	s.target = pod
+	s.container = container
+	s.stopService = stopService
+	s.mutex.Unlock()
+
+	go s.watch()
+	return nil
+}
+
+// Pod returns the pod the service is currently connected to or nil if it is reconnecting
+func (s *Supervisor) Pod() *k8sv1.Pod {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.pod
+}
+
+// Stop stops watching the pod and stops the service
+func (s *Supervisor) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.interrupt)
+
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		s.stopped = true
+		if s.stopService != nil {
+			s.stopService()
+			s.stopService = nil
+		}
+	})
+}
+
+func (s *Supervisor) watch() {
+	for {
+		select {
+		case <-s.interrupt:
+			return
+		case <-time.After(s.interval):
+		}
+
+		pod := s.Pod()
+		if pod != nil && s.isReplaced(pod) == false {
+			continue
+		}
+
+		s.reconnect(pod)
+	}
+}
+
+func (s *Supervisor) reconnect(oldPod *k8sv1.Pod) {
+	s.mutex.Lock()
+	if s.stopped {
+		s.mutex.Unlock()
+		return
+	}
+	if oldPod != nil {
+		s.log.Infof("Pod %s/%s was replaced or restarted, reconnecting %s...", oldPod.Namespace, oldPod.Name, s.name)
+
+		if s.stopService != nil {
+			s.stopService()
+			s.stopService = nil
+		}
+
+		s.pod = nil
+	}
+
+	target := s.target
+	targetContainer := s.container
+	s.mutex.Unlock()
+
+	pod, container, err := s.selectReplacement(target, targetContainer)
+	if err != nil {
+		s.log.Warnf("Unable to reconnect %s: %v (retrying)", s.name, err)
+		return
+	}
+
+	stopService, err := s.connect(pod, container)
+	if err != nil {
+		s.log.Warnf("Unable to reconnect %s to pod %s/%s: %v (retrying)", s.name, pod.Namespace, pod.Name, err)
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.stopped {
+		stopService()
+		return
+	}
+
+	s.pod = pod
+	s.target = pod
+	s.container = container
+	s.stopService = stopService
+
+	s.log.Donef("Reconnected %s to pod %s/%s", s.name, pod.Namespace, pod.Name)
+}
+
+// selectReplacement selects the newest running pod that matches the label selector of the supervisor or the labels
+// of the given pod and the container with the same name as the given container without asking the user
+func (s *Supervisor) selectReplacement(target *k8sv1.Pod, targetContainer *k8sv1.Container) (*k8sv1.Pod, *k8sv1.Container, error) {
+	labelSelector := ""
+	if s.labelSelector != nil {
+		labelSelector = *s.labelSelector
+	} else {
+		labelSelector = getReplacementSelector(target)
+		if labelSelector == "" {
+			return nil, nil, fmt.Errorf("Pod %s/%s has no labels to select its replacement", target.Namespace, target.Name)
+		}
+	}
+
+	podList, err := s.client.Core().Pods(target.Namespace).List(metav1.ListOptions{
+		LabelSelector: labelSelector,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var pod *k8sv1.Pod
+	for i := range podList.Items {
+		current := &podList.Items[i]
+		if current.DeletionTimestamp != nil || kubectl.GetPodStatus(current) != "Running" {
+			continue
+		}
+
+		if pod == nil || current.CreationTimestamp.Time.After(pod.CreationTimestamp.Time) {
+			pod = current
+		}
+	}
+	if pod == nil {
+		return nil, nil, fmt.Errorf("Couldn't find a running pod with selector %s in namespace %s", labelSelector, target.Namespace)
+	}
+	if targetContainer == nil {
+		return pod, nil, nil
+	}
+
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == targetContainer.Name {
+			return pod, &pod.Spec.Containers[i], nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("Couldn't find container %s in pod %s/%s", targetContainer.Name, pod.Namespace, pod.Name)
+}
+
+// getReplacementSelector returns a label selector of the labels the given pod shares with its replacement
+func getReplacementSelector(pod *k8sv1.Pod) string {
+	labels := make([]string, 0, len(pod.Labels))
+	for key, value := range pod.Labels {
+		if replacementIgnoredLabels[key] {
+			continue
+		}
+
+		labels = append(labels, key+"="+value)
+	}
+
+	sort.Strings(labels)
+	return strings.Join(labels, ",")
+}
+
+// isReplaced checks if the given pod was deleted, recreated, is terminating or one of its containers restarted
+func (s *Supervisor) isReplaced(pod *k8sv1.Pod) bool {
+	current, err := s.client.Core().Pods(pod.Namespace).Get(pod.Name, metav1.GetOptions{})
+	if err != nil {
+		return kerrors.IsNotFound(err)
+	}
+
+	return current.UID != pod.UID || current.DeletionTimestamp != nil || kubectl.GetPodStatus(current) != "Running" || getRestartCount(current) != getRestartCount(pod)
+}
+
+// waitForReplacement is called by a service whose connection to the given pod broke. It returns true if the
+// supervisor reconnects or stopped the service and false if the pod was not replaced
+func (s *Supervisor) waitForReplacement(pod *k8sv1.Pod) bool {
+	for waited := time.Duration(0); waited < replacementTimeout; waited += s.interval {
+		s.mutex.Lock()
+		reconnecting := s.stopped || s.pod != pod
+		s.mutex.Unlock()
+
+		if reconnecting || s.isReplaced(pod) {
+			return true
+		}
+
+		time.Sleep(s.interval)
+	}
+
+	return false
+}
+
+func getRestartCount(pod *k8sv1.Pod) int32 {
+	restartCount := int32(0)
+	for _, status := range pod.Status.ContainerStatuses {
+		restartCount += status.RestartCount
+	}
+
+	return restartCount
+}
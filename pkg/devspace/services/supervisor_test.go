@@ -0,0 +1,115 @@
+package services
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devspace-cloud/devspace/pkg/util/log"
+
+	k8sv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func createRunningPod(client kubernetes.Interface, name string, uid string, labels map[string]string) (*k8sv1.Pod, error) {
+	return client.Core().Pods("default").Create(&k8sv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			UID:       types.UID(uid),
+			Labels:    labels,
+		},
+		Status: k8sv1.PodStatus{
+			Phase: k8sv1.PodRunning,
+		},
+	})
+}
+
+func TestSupervisorReconnect(t *testing.T) {
+	supervisorInterval = time.Millisecond * 10
+
+	client := fake.NewSimpleClientset()
+	_, err := createRunningPod(client, "pod-a", "a", map[string]string{"app": "test", "pod-template-hash": "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mutex sync.Mutex
+	selected := 0
+	connected := []string{}
+	stopped := []string{}
+
+	supervisor := NewSupervisor("test", client, nil, func() (*k8sv1.Pod, *k8sv1.Container, error) {
+		mutex.Lock()
+		selected++
+		mutex.Unlock()
+
+		podList, err := client.Core().Pods("default").List(metav1.ListOptions{})
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(podList.Items) == 0 {
+			return nil, nil, errors.New("No pod found")
+		}
+
+		return &podList.Items[0], nil, nil
+	}, func(pod *k8sv1.Pod, container *k8sv1.Container) (func(), error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		connected = append(connected, pod.Name)
+		return func() {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			stopped = append(stopped, pod.Name)
+		}, nil
+	}, &log.DiscardLogger{})
+
+	err = supervisor.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Replace the pod
+	err = client.Core().Pods("default").Delete("pod-a", &metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = createRunningPod(client, "pod-other", "other", map[string]string{"app": "other"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = createRunningPod(client, "pod-b", "b", map[string]string{"app": "test", "pod-template-hash": "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 200; i++ {
+		pod := supervisor.Pod()
+		if pod != nil && pod.Name == "pod-b" {
+			break
+		}
+
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	supervisor.Stop()
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if selected != 1 {
+		t.Fatalf("Expected the target to be selected once and the replacement without asking, got %d selections", selected)
+	}
+	if len(connected) != 2 || connected[0] != "pod-a" || connected[1] != "pod-b" {
+		t.Fatalf("Expected connections to pod-a and pod-b, got %v", connected)
+	}
+	if len(stopped) != 2 || stopped[0] != "pod-a" || stopped[1] != "pod-b" {
+		t.Fatalf("Expected pod-a and pod-b to be stopped, got %v", stopped)
+	}
+}
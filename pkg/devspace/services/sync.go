@@ -3,77 +3,126 @@ package services
 import (
 	"fmt"
 	"path/filepath"
+	"strconv"
 
 	"github.com/devspace-cloud/devspace/pkg/devspace/services/targetselector"
 
+	k8sv1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/devspace-cloud/devspace/pkg/devspace/config/versions/latest"
 	"github.com/devspace-cloud/devspace/pkg/devspace/sync"
+	"github.com/devspace-cloud/devspace/pkg/util/ignoreutil"
 	"github.com/devspace-cloud/devspace/pkg/util/log"
 )
 
+// SyncCmdOptions holds the options of a sync that is started from command
+type SyncCmdOptions struct {
+	ContainerPath string
+	Exclude       []string
+
+	UploadOnly   bool
+	DownloadOnly bool
+	Delete       bool
+}
+
 // StartSyncFromCmd starts a new sync from command
-func StartSyncFromCmd(config *latest.Config, client kubernetes.Interface, cmdParameter targetselector.CmdParameter, containerPath string, exclude []string, log log.Logger) error {
+func StartSyncFromCmd(config *latest.Config, client kubernetes.Interface, cmdParameter targetselector.CmdParameter, options *SyncCmdOptions, log log.Logger) error {
+	syncDone := make(chan bool)
+
+	syncConfig, err := newSyncConfigFromCmd(config, client, cmdParameter, options, log)
+	if err != nil {
+		return err
+	}
+
+	syncConfig.SyncDone = syncDone
+
+	direction := "<->"
+	if options.UploadOnly {
+		direction = "->"
+	} else if options.DownloadOnly {
+		direction = "<-"
+	}
+
+	log.Donef("Sync started on %s %s %s (Pod: %s/%s)", syncConfig.WatchPath, direction, syncConfig.DestPath, syncConfig.Pod.Namespace, syncConfig.Pod.Name)
+
+	err = syncConfig.Start()
+	if err != nil {
+		log.Fatalf("Sync error: %s", err.Error())
+	}
+
+	// Wait till sync is finished
+	<-syncDone
+
+	return nil
+}
+
+// RunSyncOnceFromCmd synchronizes the local path and the container a single time and returns the applied changes.
+// If dryRun is true, the changes are only calculated and no files are changed
+func RunSyncOnceFromCmd(config *latest.Config, client kubernetes.Interface, cmdParameter targetselector.CmdParameter, options *SyncCmdOptions, dryRun bool, log log.Logger) (*sync.Plan, error) {
+	syncConfig, err := newSyncConfigFromCmd(config, client, cmdParameter, options, log)
+	if err != nil {
+		return nil, err
+	}
+
+	if dryRun {
+		return syncConfig.Diff()
+	}
+
+	return syncConfig.RunOnce()
+}
+
+func newSyncConfigFromCmd(config *latest.Config, client kubernetes.Interface, cmdParameter targetselector.CmdParameter, options *SyncCmdOptions, log log.Logger) (*sync.SyncConfig, error) {
 	var (
 		localPath = "."
 	)
 
 	absLocalPath, err := filepath.Abs(localPath)
 	if err != nil {
-		return fmt.Errorf("Unable to resolve localSubPath %s: %v", localPath, err)
+		return nil, fmt.Errorf("Unable to resolve localSubPath %s: %v", localPath, err)
 	}
 
 	targetSelector, err := targetselector.NewTargetSelector(config, &targetselector.SelectorParameter{
 		CmdParameter: cmdParameter,
 	}, true)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	pod, container, err := targetSelector.GetContainer(client)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	containerPath := options.ContainerPath
 	if containerPath == "" {
 		containerPath = "."
 	}
 
-	syncDone := make(chan bool)
-	syncConfig := &sync.SyncConfig{
+	return &sync.SyncConfig{
 		DevSpaceConfig: config,
 		Kubectl:        client,
 		Pod:            pod,
 		Container:      container,
 		WatchPath:      absLocalPath,
 		DestPath:       containerPath,
-		ExcludePaths:   exclude,
+		ExcludePaths:   options.Exclude,
+		UploadOnly:     options.UploadOnly,
+		DownloadOnly:   options.DownloadOnly,
+		Delete:         options.Delete,
 		CustomLog:      log,
-		SyncDone:       syncDone,
 		Verbose:        false,
-	}
-
-	log.Donef("Sync started on %s <-> %s (Pod: %s/%s)", absLocalPath, containerPath, pod.Namespace, pod.Name)
-
-	err = syncConfig.Start()
-	if err != nil {
-		log.Fatalf("Sync error: %s", err.Error())
-	}
-
-	// Wait till sync is finished
-	<-syncDone
-
-	return nil
+	}, nil
 }
 
-// StartSync starts the syncing functionality
-func StartSync(config *latest.Config, client kubernetes.Interface, verboseSync bool, log log.Logger) ([]*sync.SyncConfig, error) {
+// StartSync starts the syncing functionality. Each sync path is supervised and restarted with a fresh
+// initial sync if the target pod is replaced
+func StartSync(config *latest.Config, client kubernetes.Interface, verboseSync bool, log log.Logger) ([]*Supervisor, error) {
 	if config.Dev.Sync == nil {
-		return []*sync.SyncConfig{}, nil
+		return []*Supervisor{}, nil
 	}
 
-	syncConfigs := make([]*sync.SyncConfig, 0, len(*config.Dev.Sync))
+	supervisors := make([]*Supervisor, 0, len(*config.Dev.Sync))
 	for _, syncPath := range *config.Dev.Sync {
 		localPath := "."
 		if syncPath.LocalSubPath != nil {
@@ -97,67 +146,73 @@ func StartSync(config *latest.Config, client kubernetes.Interface, verboseSync b
 			return nil, fmt.Errorf("Error creating target selector: %v", err)
 		}
 
-		log.StartWait("Sync: Waiting for pods...")
-		pod, container, err := selector.GetContainer(client)
-		log.StopWait()
-		if err != nil {
-			return nil, fmt.Errorf("Unable to start sync, because an error occured during pod selection: %v", err)
-		}
-
 		containerPath := "."
 		if syncPath.ContainerPath != nil {
 			containerPath = *syncPath.ContainerPath
 		}
 
-		var upstreamInitialSyncDone chan bool
-		var downstreamInitialSyncDone chan bool
-
-		if syncPath.WaitInitialSync != nil && *syncPath.WaitInitialSync == true {
-			upstreamInitialSyncDone = make(chan bool)
-			downstreamInitialSyncDone = make(chan bool)
+		excludePaths, err := GetExcludePaths(syncPath, absLocalPath)
+		if err != nil {
+			return nil, err
 		}
 
-		syncConfig := &sync.SyncConfig{
-			DevSpaceConfig:            config,
-			Kubectl:                   client,
-			Pod:                       pod,
-			Container:                 container,
-			WatchPath:                 absLocalPath,
-			DestPath:                  containerPath,
-			Verbose:                   verboseSync,
-			UpstreamInitialSyncDone:   upstreamInitialSyncDone,
-			DownstreamInitialSyncDone: downstreamInitialSyncDone,
-		}
+		var syncConfig *sync.SyncConfig
 
-		if syncPath.ExcludePaths != nil {
-			syncConfig.ExcludePaths = *syncPath.ExcludePaths
-		}
+		syncPath := syncPath
+		supervisor := NewSupervisor("sync "+absLocalPath+" <-> "+containerPath, client, selector.GetLabelSelector(), func() (*k8sv1.Pod, *k8sv1.Container, error) {
+			return selector.GetContainer(client)
+		}, func(pod *k8sv1.Pod, container *k8sv1.Container) (func(), error) {
+			syncConfig = newSyncConfig(config, client, syncPath, pod, container, absLocalPath, containerPath, excludePaths, verboseSync)
 
-		if syncPath.DownloadExcludePaths != nil {
-			syncConfig.DownloadExcludePaths = *syncPath.DownloadExcludePaths
-		}
+			err := syncConfig.Start()
+			if err != nil {
+				return nil, err
+			}
 
-		if syncPath.UploadExcludePaths != nil {
-			syncConfig.UploadExcludePaths = *syncPath.UploadExcludePaths
-		}
+			log.Donef("Sync started on %s <-> %s (Pod: %s/%s)", absLocalPath, containerPath, pod.Namespace, pod.Name)
 
-		if syncPath.BandwidthLimits != nil {
-			if syncPath.BandwidthLimits.Download != nil {
-				syncConfig.DownstreamLimit = *syncPath.BandwidthLimits.Download * 1024
+			stoppedConfig := syncConfig
+			if syncPath.AllReplicas == nil || *syncPath.AllReplicas == false {
+				return func() {
+					stoppedConfig.Stop(nil)
+				}, nil
 			}
 
-			if syncPath.BandwidthLimits.Upload != nil {
-				syncConfig.UpstreamLimit = *syncPath.BandwidthLimits.Upload * 1024
-			}
-		}
+			// Upload to all other replicas, downstream only runs for the primary pod
+			replicas := newReplicaSync(pod, container, func() ([]*k8sv1.Pod, error) {
+				return selector.GetRunningPods(client)
+			}, func(replica *k8sv1.Pod, replicaContainer *k8sv1.Container) (func(), error) {
+				replicaConfig := newSyncConfig(config, client, syncPath, replica, replicaContainer, absLocalPath, containerPath, excludePaths, verboseSync)
+				replicaConfig.UploadOnly = true
+				replicaConfig.ConflictPolicy = ""
+				replicaConfig.UpstreamInitialSyncDone = nil
+				replicaConfig.DownstreamInitialSyncDone = nil
+
+				err := replicaConfig.Start()
+				if err != nil {
+					return nil, err
+				}
+
+				log.Donef("Sync started on %s -> %s (Replica: %s/%s)", absLocalPath, containerPath, replica.Namespace, replica.Name)
+				return func() {
+					replicaConfig.Stop(nil)
+				}, nil
+			}, log)
+			replicas.Start()
+
+			return func() {
+				replicas.Stop()
+				stoppedConfig.Stop(nil)
+			}, nil
+		}, log)
 
-		err = syncConfig.Start()
+		log.StartWait("Sync: Waiting for pods...")
+		err = supervisor.Start()
+		log.StopWait()
 		if err != nil {
-			log.Fatalf("Sync error: %s", err.Error())
+			return nil, fmt.Errorf("Unable to start sync: %v", err)
 		}
 
-		log.Donef("Sync started on %s <-> %s (Pod: %s/%s)", absLocalPath, containerPath, pod.Namespace, pod.Name)
-
 		if syncPath.WaitInitialSync != nil && *syncPath.WaitInitialSync == true {
 			log.StartWait("Sync: waiting for intial sync to complete")
 			<-syncConfig.UpstreamInitialSyncDone
@@ -165,8 +220,129 @@ func StartSync(config *latest.Config, client kubernetes.Interface, verboseSync b
 			log.StopWait()
 		}
 
-		syncConfigs = append(syncConfigs, syncConfig)
+		supervisors = append(supervisors, supervisor)
+	}
+
+	return supervisors, nil
+}
+
+// GetExcludePaths returns the effective exclude paths of the sync path including the rules of .gitignore and .dockerignore files if enabled
+func GetExcludePaths(syncPath *latest.SyncConfig, absLocalPath string) ([]string, error) {
+	excludePaths := []string{}
+	if syncPath.ExcludePaths != nil {
+		excludePaths = append(excludePaths, *syncPath.ExcludePaths...)
+	}
+
+	if syncPath.UseGitIgnore != nil && *syncPath.UseGitIgnore == true {
+		ignoreRules, err := ignoreutil.GetGitIgnoreRules(absLocalPath)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to read .gitignore files in %s: %v", absLocalPath, err)
+		}
+
+		excludePaths = append(excludePaths, ignoreRules...)
+	}
+
+	if syncPath.UseDockerIgnore != nil && *syncPath.UseDockerIgnore == true {
+		ignoreRules, err := ignoreutil.GetIgnoreRules(absLocalPath)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to read .dockerignore files in %s: %v", absLocalPath, err)
+		}
+
+		excludePaths = append(excludePaths, ignoreRules...)
+	}
+
+	return excludePaths, nil
+}
+
+func newSyncConfig(config *latest.Config, client kubernetes.Interface, syncPath *latest.SyncConfig, pod *k8sv1.Pod, container *k8sv1.Container, absLocalPath, containerPath string, excludePaths []string, verboseSync bool) *sync.SyncConfig {
+	var upstreamInitialSyncDone chan bool
+	var downstreamInitialSyncDone chan bool
+
+	if syncPath.WaitInitialSync != nil && *syncPath.WaitInitialSync == true {
+		upstreamInitialSyncDone = make(chan bool)
+		downstreamInitialSyncDone = make(chan bool)
+	}
+
+	syncConfig := &sync.SyncConfig{
+		DevSpaceConfig:            config,
+		Kubectl:                   client,
+		Pod:                       pod,
+		Container:                 container,
+		WatchPath:                 absLocalPath,
+		DestPath:                  containerPath,
+		ExcludePaths:              append([]string{}, excludePaths...),
+		Verbose:                   verboseSync,
+		UpstreamInitialSyncDone:   upstreamInitialSyncDone,
+		DownstreamInitialSyncDone: downstreamInitialSyncDone,
+	}
+
+	if syncPath.DownloadExcludePaths != nil {
+		syncConfig.DownloadExcludePaths = *syncPath.DownloadExcludePaths
+	}
+
+	if syncPath.UploadExcludePaths != nil {
+		syncConfig.UploadExcludePaths = *syncPath.UploadExcludePaths
+	}
+
+	if syncPath.UseAgent != nil {
+		syncConfig.UseAgent = *syncPath.UseAgent
+	}
+
+	if syncPath.Checksum != nil {
+		syncConfig.Checksum = *syncPath.Checksum
+	}
+
+	if syncPath.ConflictPolicy != nil {
+		syncConfig.ConflictPolicy = *syncPath.ConflictPolicy
+	}
+
+	if syncPath.FileMode != nil {
+		// The mode was already validated when the config was loaded
+		fileMode, _ := strconv.ParseInt(*syncPath.FileMode, 8, 32)
+		syncConfig.FileMode = fileMode
+	}
+
+	if syncPath.DirMode != nil {
+		dirMode, _ := strconv.ParseInt(*syncPath.DirMode, 8, 32)
+		syncConfig.DirMode = dirMode
+	}
+
+	syncConfig.UID = syncPath.UID
+	syncConfig.GID = syncPath.GID
+
+	if syncPath.Compression != nil {
+		syncConfig.Compression = *syncPath.Compression
+	}
+
+	if syncPath.OnUpload != nil {
+		for _, onUpload := range *syncPath.OnUpload {
+			onUploadCommand := &sync.OnUploadCommand{
+				Command: []string{},
+			}
+
+			if onUpload.Paths != nil {
+				onUploadCommand.Paths = *onUpload.Paths
+			}
+
+			if onUpload.Command != nil {
+				for _, arg := range *onUpload.Command {
+					onUploadCommand.Command = append(onUploadCommand.Command, *arg)
+				}
+			}
+
+			syncConfig.OnUpload = append(syncConfig.OnUpload, onUploadCommand)
+		}
+	}
+
+	if syncPath.BandwidthLimits != nil {
+		if syncPath.BandwidthLimits.Download != nil {
+			syncConfig.DownstreamLimit = *syncPath.BandwidthLimits.Download * 1024
+		}
+
+		if syncPath.BandwidthLimits.Upload != nil {
+			syncConfig.UpstreamLimit = *syncPath.BandwidthLimits.Upload * 1024
+		}
 	}
 
-	return syncConfigs, nil
+	return syncConfig
 }
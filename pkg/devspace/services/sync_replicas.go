@@ -0,0 +1,145 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/devspace-cloud/devspace/pkg/util/log"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ListFunc lists all running pods a service should fan out to
+type ListFunc func() ([]*k8sv1.Pod, error)
+
+// replicaSync fans out the uploads of a sync path to every replica besides the primary pod. Replicas that
+// appear later are picked up and replicas that are gone are stopped
+type replicaSync struct {
+	primary       *k8sv1.Pod
+	containerName string
+	listReplicas  ListFunc
+	connect       ConnectFunc
+	log           log.Logger
+	interval      time.Duration
+
+	replicas map[types.UID]func()
+	mutex    sync.Mutex
+
+	interrupt chan bool
+	stopOnce  sync.Once
+}
+
+func newReplicaSync(primary *k8sv1.Pod, container *k8sv1.Container, listReplicas ListFunc, connect ConnectFunc, log log.Logger) *replicaSync {
+	containerName := ""
+	if container != nil {
+		containerName = container.Name
+	}
+
+	return &replicaSync{
+		primary:       primary,
+		containerName: containerName,
+		listReplicas:  listReplicas,
+		connect:       connect,
+		log:           log,
+		interval:      supervisorInterval,
+		replicas:      make(map[types.UID]func()),
+		interrupt:     make(chan bool),
+	}
+}
+
+// Start connects to all current replicas and starts watching for new ones
+func (r *replicaSync) Start() {
+	r.update()
+
+	go func() {
+		for {
+			select {
+			case <-r.interrupt:
+				return
+			case <-time.After(r.interval):
+			}
+
+			r.update()
+		}
+	}()
+}
+
+// Stop stops watching for replicas and stops the connections to all replicas
+func (r *replicaSync) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.interrupt)
+
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+
+		for uid, stop := range r.replicas {
+			stop()
+			delete(r.replicas, uid)
+		}
+	})
+}
+
+func (r *replicaSync) update() {
+	pods, err := r.listReplicas()
+	if err != nil {
+		r.log.Warnf("Unable to list replicas of pod %s/%s: %v (retrying)", r.primary.Namespace, r.primary.Name, err)
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	select {
+	case <-r.interrupt:
+		return
+	default:
+	}
+
+	running := make(map[types.UID]bool)
+	for _, pod := range pods {
+		if pod.UID == r.primary.UID {
+			continue
+		}
+
+		running[pod.UID] = true
+		if r.replicas[pod.UID] != nil {
+			continue
+		}
+
+		container, ok := findContainer(pod, r.containerName)
+		if ok == false {
+			r.log.Warnf("Skip replica %s/%s, because it has no container %s", pod.Namespace, pod.Name, r.containerName)
+			continue
+		}
+
+		stop, err := r.connect(pod, container)
+		if err != nil {
+			r.log.Warnf("Unable to connect to replica %s/%s: %v (retrying)", pod.Namespace, pod.Name, err)
+			continue
+		}
+
+		r.replicas[pod.UID] = stop
+	}
+
+	// Stop the connections to replicas that are gone
+	for uid, stop := range r.replicas {
+		if running[uid] == false {
+			stop()
+			delete(r.replicas, uid)
+		}
+	}
+}
+
+func findContainer(pod *k8sv1.Pod, containerName string) (*k8sv1.Container, bool) {
+	if containerName == "" && len(pod.Spec.Containers) == 1 {
+		return &pod.Spec.Containers[0], true
+	}
+
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == containerName {
+			return &pod.Spec.Containers[i], true
+		}
+	}
+
+	return nil, false
+}
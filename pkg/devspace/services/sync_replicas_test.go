@@ -0,0 +1,79 @@
+package services
+
+import (
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/devspace-cloud/devspace/pkg/util/log"
+
+	k8sv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func createReplica(name string) *k8sv1.Pod {
+	return &k8sv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			UID:       types.UID(name),
+		},
+		Spec: k8sv1.PodSpec{
+			Containers: []k8sv1.Container{{Name: "app"}, {Name: "sidecar"}},
+		},
+		Status: k8sv1.PodStatus{
+			ContainerStatuses: []k8sv1.ContainerStatus{
+				{Name: "app", State: k8sv1.ContainerState{Running: &k8sv1.ContainerStateRunning{}}},
+				{Name: "sidecar", State: k8sv1.ContainerState{Running: &k8sv1.ContainerStateRunning{}}},
+			},
+		},
+	}
+}
+
+func TestReplicaSync(t *testing.T) {
+	primary := createReplica("pod-a")
+	pods := []*k8sv1.Pod{primary, createReplica("pod-b"), createReplica("pod-c")}
+
+	var mutex sync.Mutex
+	connected := []string{}
+	stopped := []string{}
+
+	replicas := newReplicaSync(primary, &primary.Spec.Containers[0], func() ([]*k8sv1.Pod, error) {
+		return pods, nil
+	}, func(pod *k8sv1.Pod, container *k8sv1.Container) (func(), error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if container.Name != "app" {
+			t.Fatalf("Expected container app, got %s", container.Name)
+		}
+
+		connected = append(connected, pod.Name)
+		return func() {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			stopped = append(stopped, pod.Name)
+		}, nil
+	}, &log.DiscardLogger{})
+
+	// The primary is never connected as replica
+	replicas.update()
+
+	// pod-b is gone and pod-d appeared
+	pods = []*k8sv1.Pod{primary, pods[2], createReplica("pod-d")}
+	replicas.update()
+
+	replicas.Stop()
+
+	sort.Strings(connected)
+	sort.Strings(stopped)
+
+	if len(connected) != 3 || connected[0] != "pod-b" || connected[1] != "pod-c" || connected[2] != "pod-d" {
+		t.Fatalf("Expected connections to pod-b, pod-c and pod-d, got %v", connected)
+	}
+	if len(stopped) != 3 || stopped[0] != "pod-b" || stopped[1] != "pod-c" || stopped[2] != "pod-d" {
+		t.Fatalf("Expected pod-b, pod-c and pod-d to be stopped, got %v", stopped)
+	}
+}
@@ -100,6 +100,50 @@ func (t *TargetSelector) GetPod(client kubernetes.Interface) (*v1.Pod, error) {
 	return pod, nil
 }
 
+// GetLabelSelector returns the label selector the pod is selected by or nil if the pod is selected by name or picked
+// by the user
+func (t *TargetSelector) GetLabelSelector() *string {
+	if t.pick || t.podName != nil {
+		return nil
+	}
+
+	return t.labelSelector
+}
+
+// GetRunningPods retrieves all running pods that match the pod name or label selector without asking the user
+func (t *TargetSelector) GetRunningPods(client kubernetes.Interface) ([]*v1.Pod, error) {
+	if t.podName != nil {
+		pod, err := client.Core().Pods(t.namespace).Get(*t.podName, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+
+		if kubectl.GetPodStatus(pod) != "Running" {
+			return []*v1.Pod{}, nil
+		}
+
+		return []*v1.Pod{pod}, nil
+	} else if t.labelSelector == nil {
+		return nil, errors.New("Couldn't list pods, because no labelselector or pod name was specified")
+	}
+
+	podList, err := client.Core().Pods(t.namespace).List(metav1.ListOptions{
+		LabelSelector: *t.labelSelector,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pods := []*v1.Pod{}
+	for i := range podList.Items {
+		if podList.Items[i].DeletionTimestamp == nil && kubectl.GetPodStatus(&podList.Items[i]) == "Running" {
+			pods = append(pods, &podList.Items[i])
+		}
+	}
+
+	return pods, nil
+}
+
 // GetContainer retrieves a container and pod
 func (t *TargetSelector) GetContainer(client kubernetes.Interface) (*v1.Pod, *v1.Container, error) {
 	pod, err := t.GetPod(client)
@@ -10,6 +10,7 @@ import (
 	"github.com/devspace-cloud/devspace/pkg/util/log"
 	"github.com/devspace-cloud/devspace/pkg/util/ptr"
 	"github.com/mgutz/ansi"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
 	kubectlExec "k8s.io/client-go/util/exec"
 )
@@ -38,37 +39,51 @@ func StartTerminal(config *latest.Config, client kubernetes.Interface, cmdParame
 
 	targetSelector.PodQuestion = ptr.String("Which pod do you want to open the terminal for?")
 
-	pod, container, err := targetSelector.GetContainer(client)
-	if err != nil {
-		return err
-	}
+	var supervisor *Supervisor
+	supervisor = NewSupervisor("terminal", client, targetSelector.GetLabelSelector(), func() (*v1.Pod, *v1.Container, error) {
+		return targetSelector.GetContainer(client)
+	}, func(pod *v1.Pod, container *v1.Container) (func(), error) {
+		kubeconfig, err := kubectl.GetClientConfig(config)
+		if err != nil {
+			return nil, err
+		}
 
-	kubeconfig, err := kubectl.GetClientConfig(config)
-	if err != nil {
-		return err
-	}
+		wrapper, upgradeRoundTripper, err := kubectl.GetUpgraderWrapper(kubeconfig)
+		if err != nil {
+			return nil, err
+		}
 
-	wrapper, upgradeRoundTripper, err := kubectl.GetUpgraderWrapper(kubeconfig)
-	if err != nil {
-		return err
-	}
+		log.Infof("Opening shell to pod:container %s:%s", ansi.Color(pod.Name, "white+b"), ansi.Color(container.Name, "white+b"))
 
-	log.Infof("Opening shell to pod:container %s:%s", ansi.Color(pod.Name, "white+b"), ansi.Color(container.Name, "white+b"))
+		go func() {
+			terminalErr := kubectl.ExecStreamWithTransport(wrapper, upgradeRoundTripper, client, pod, container.Name, command, true, os.Stdin, os.Stdout, os.Stderr)
+			if terminalErr != nil {
+				if _, ok := terminalErr.(kubectlExec.CodeExitError); ok == false {
+					// The supervisor opens a new shell if the pod was replaced
+					if supervisor.waitForReplacement(pod) {
+						return
+					}
 
-	go func() {
-		terminalErr := kubectl.ExecStreamWithTransport(wrapper, upgradeRoundTripper, client, pod, container.Name, command, true, os.Stdin, os.Stdout, os.Stderr)
-		if terminalErr != nil {
-			if _, ok := terminalErr.(kubectlExec.CodeExitError); ok == false {
-				interrupt <- fmt.Errorf("Unable to start terminal session: %v", terminalErr)
-				return
+					interrupt <- fmt.Errorf("Unable to start terminal session: %v", terminalErr)
+					return
+				}
 			}
-		}
 
-		interrupt <- nil
-	}()
+			interrupt <- nil
+		}()
+
+		return func() {
+			upgradeRoundTripper.Close()
+		}, nil
+	}, log)
+
+	err = supervisor.Start()
+	if err != nil {
+		return err
+	}
 
 	err = <-interrupt
-	upgradeRoundTripper.Close()
+	supervisor.Stop()
 	return err
 }
 
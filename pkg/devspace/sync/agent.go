@@ -0,0 +1,488 @@
+package sync
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/versions/latest"
+	"github.com/devspace-cloud/devspace/pkg/devspace/kubectl"
+	"github.com/devspace-cloud/devspace/pkg/devspace/sync/agent"
+	"github.com/devspace-cloud/devspace/pkg/devspace/upgrade"
+	"github.com/juju/errors"
+	"github.com/juju/ratelimit"
+	homedir "github.com/mitchellh/go-homedir"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// AgentBinaryEnv can be set to a local sync agent binary that should be injected into the container
+const AgentBinaryEnv = "DEVSPACE_SYNC_AGENT"
+
+// AgentFolder is the folder in the home directory where downloaded sync agents are cached
+const AgentFolder = ".devspace/bin"
+
+// agentRemotePath is the path in the container the sync agent is copied to. The checksum of the binary is appended
+const agentRemotePath = "/tmp/devspace-sync-agent"
+
+// agentChecksumLength is the amount of checksum characters that are appended to the remote path
+const agentChecksumLength = 12
+
+// agentDownloadURL is the release url the sync agent is downloaded from if it is not found locally
+const agentDownloadURL = "https://github.com/devspace-cloud/devspace/releases/download/v%s/devspace-sync-agent-linux-%s"
+
+// agentStartTimeout is the time we wait for the agent to signal that it is watching
+const agentStartTimeout = time.Second * 20
+
+// Maps the output of uname -m to the go architecture the agent was built for
+var agentArchitectures = map[string]string{
+	"x86_64":  "amd64",
+	"amd64":   "amd64",
+	"i386":    "386",
+	"i686":    "386",
+	"ppc64":   "ppc64",
+	"ppc64le": "ppc64le",
+}
+
+// startAgent injects the sync agent into the container and starts it. If this function returns an error,
+// the downstream falls back to polling the container with the find command
+func (d *downstream) startAgent() error {
+	stdinReader, stdinWriter, _ := os.Pipe()
+	stdoutReader, stdoutWriter, _ := os.Pipe()
+	errorChan := make(chan error, 1)
+
+	if d.config.testing == false {
+		remotePath, err := d.injectAgent()
+		if err != nil {
+			return errors.Trace(err)
+		}
+
+		go func() {
+			command := []string{remotePath, d.config.DestPath}
+			if d.config.Checksum {
+				command = []string{remotePath, "--checksum", d.config.DestPath}
+			}
+
+			errorChan <- kubectl.ExecStream(d.config.DevSpaceConfig, d.config.Kubectl, d.config.Pod, d.config.Container.Name, command, false, stdinReader, stdoutWriter, nil)
+			stdoutWriter.Close()
+		}()
+	} else {
+		// In tests we run the agent in process
+		watcher, err := agent.NewWatcher(d.config.DestPath, stdoutWriter)
+		if err != nil {
+			return errors.Trace(err)
+		}
+
+		watcher.Checksum = d.config.Checksum
+
+		stop := make(chan bool)
+		go func() {
+			io.Copy(ioutil.Discard, stdinReader)
+			close(stop)
+		}()
+		go func() {
+			errorChan <- watcher.Watch(stop)
+			stdoutWriter.Close()
+		}()
+	}
+
+	readyChan := make(chan error, 1)
+	go func() {
+		readyChan <- waitTill(agent.ReadyAck, stdoutReader)
+	}()
+
+	select {
+	case err := <-errorChan:
+		if err == nil {
+			err = errors.New("agent exited unexpectedly")
+		}
+
+		stdinWriter.Close()
+		return errors.Trace(err)
+	case err := <-readyChan:
+		if err != nil {
+			stdinWriter.Close()
+			return errors.Trace(err)
+		}
+	case <-time.After(agentStartTimeout):
+		stdinWriter.Close()
+		return errors.New("Timeout waiting for sync agent to start")
+	}
+
+	d.agentMutex.Lock()
+	defer d.agentMutex.Unlock()
+
+	d.agentStdin = stdinWriter
+	d.agentStdout = stdoutReader
+	return nil
+}
+
+// InjectAgent starts a shell in the given container, copies the sync agent into it and returns the remote path.
+// Besides the downstream, the agent is used as listener for reverse port forwardings
+func InjectAgent(config *latest.Config, client kubernetes.Interface, pod *k8sv1.Pod, container string, logf func(format string, args ...interface{})) (string, error) {
+	stdinReader, stdinWriter, err := os.Pipe()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+
+	stdoutReader, stdoutWriter, err := os.Pipe()
+	if err != nil {
+		stdinReader.Close()
+		stdinWriter.Close()
+		return "", errors.Trace(err)
+	}
+
+	// Closing stdin ends the shell, closing stdout unblocks the shell if it still writes
+	defer stdinWriter.Close()
+	defer stdoutReader.Close()
+
+	errorChan := make(chan error, 1)
+	go func() {
+		errorChan <- kubectl.ExecStream(config, client, pod, container, []string{"sh"}, false, stdinReader, stdoutWriter, nil)
+		stdinReader.Close()
+		stdoutWriter.Close()
+	}()
+
+	remotePath, err := injectAgent(stdinWriter, stdoutReader, logf)
+	if err != nil {
+		select {
+		case execErr := <-errorChan:
+			if execErr != nil {
+				return "", errors.Trace(execErr)
+			}
+		default:
+		}
+
+		return "", errors.Trace(err)
+	}
+
+	return remotePath, nil
+}
+
+// injectAgent copies the sync agent through the downstream shell into the container and returns the remote path
+func (d *downstream) injectAgent() (string, error) {
+	return injectAgent(d.stdinPipe, d.stdoutPipe, func(format string, args ...interface{}) {
+		d.config.Logf("[Downstream] "+format, args...)
+	})
+}
+
+// injectAgent copies the sync agent through an already running shell into the container. The remote file name
+// contains the checksum of the binary, so that an agent of another build is never reused
+func injectAgent(stdinPipe io.Writer, stdoutPipe io.Reader, logf func(format string, args ...interface{})) (string, error) {
+	cmd := "uname -m 2>/dev/null || echo unknown; echo \"" + EndAck + "\"\n"
+	_, err := stdinPipe.Write([]byte(cmd))
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+
+	readString, err := readTill(EndAck, stdoutPipe)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+
+	arch, ok := agentArchitectures[strings.TrimSpace(readString)]
+	if ok == false {
+		return "", fmt.Errorf("Unsupported container architecture %s", strings.TrimSpace(readString))
+	}
+
+	localPath, err := getAgentBinary(arch)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+
+	stat, err := os.Stat(localPath)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+
+	checksum, err := agent.Checksum(localPath)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+
+	remotePath := agentRemotePath + "-" + checksum[:agentChecksumLength]
+
+	cmd = "stat -c \"%s\" '" + remotePath + "' 2>/dev/null || echo 0; echo \"" + EndAck + "\"\n"
+	_, err = stdinPipe.Write([]byte(cmd))
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+
+	readString, err = readTill(EndAck, stdoutPipe)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+
+	// Agent is already there from a previous session
+	if strings.TrimSpace(readString) == strconv.FormatInt(stat.Size(), 10) {
+		return remotePath, nil
+	}
+
+	f, err := os.Open(localPath)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+
+	defer f.Close()
+
+	logf("Inject sync agent into container (size %d)", stat.Size())
+
+	cmd = "fileSize=" + strconv.FormatInt(stat.Size(), 10) + `;
+					tmpFile="` + remotePath + `.tmp";
+					mkdir -p /tmp;
+
+					pid=$$;
+					cat </proc/$pid/fd/0 >"$tmpFile" &
+					ddPid=$!;
+
+					echo "` + StartAck + `";
+
+					while true; do
+							bytesRead=$(stat -c "%s" "$tmpFile" 2>/dev/null || printf "0");
+
+							if [ "$bytesRead" = "$fileSize" ]; then
+									kill $ddPid;
+									break;
+							fi;
+
+							sleep 0.1;
+					done;
+
+					chmod +x "$tmpFile" && mv "$tmpFile" '` + remotePath + `' 2>/tmp/devspace-agent-error;
+					echo "` + EndAck + `";
+		` // We need that extra new line or otherwise the command is not sent
+
+	_, err = stdinPipe.Write([]byte(cmd))
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+
+	err = waitTill(StartAck, stdoutPipe)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+
+	_, err = io.Copy(stdinPipe, f)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+
+	err = waitTill(EndAck, stdoutPipe)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+
+	return remotePath, nil
+}
+
+// getAgentBinary returns the path to a local sync agent binary for the given architecture
+// and downloads it from the release page if necessary
+func getAgentBinary(arch string) (string, error) {
+	if envPath := os.Getenv(AgentBinaryEnv); envPath != "" {
+		return envPath, nil
+	}
+
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+
+	version := upgrade.GetVersion()
+	if version == "" {
+		version = "latest"
+	}
+
+	localPath := filepath.Join(home, filepath.FromSlash(AgentFolder), "devspace-sync-agent-"+version+"-linux-"+arch)
+
+	_, err = os.Stat(localPath)
+	if err == nil {
+		return localPath, nil
+	}
+
+	if version == "latest" {
+		return "", fmt.Errorf("Sync agent binary not found, please specify it via %s", AgentBinaryEnv)
+	}
+
+	err = os.MkdirAll(filepath.Dir(localPath), 0755)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+
+	resp, err := http.Get(fmt.Sprintf(agentDownloadURL, version, arch))
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Error downloading sync agent: %s", resp.Status)
+	}
+
+	tempFile, err := os.Create(localPath + ".download")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+
+	_, err = io.Copy(tempFile, resp.Body)
+	tempFile.Close()
+	if err != nil {
+		os.Remove(tempFile.Name())
+		return "", errors.Trace(err)
+	}
+
+	err = os.Rename(tempFile.Name(), localPath)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+
+	return localPath, nil
+}
+
+// agentLoop waits for change batches from the sync agent and applies them. If the agent stream
+// breaks, we fall back to polling
+func (d *downstream) agentLoop() error {
+	agentStdout := d.getAgentStdout()
+	if agentStdout == nil {
+		return d.pollLoop()
+	}
+
+	batches := make(chan []string)
+	errorChan := make(chan error, 1)
+
+	go func() {
+		errorChan <- d.readAgentBatches(agentStdout, batches)
+	}()
+
+	for {
+		select {
+		case <-d.interrupt:
+			return nil
+		case err := <-errorChan:
+			d.config.Logf("[Downstream] Sync agent stopped (%v), falling back to polling", err)
+			d.stopAgent()
+
+			return d.pollLoop()
+		case batch := <-batches:
+			createFiles, removeFiles, err := d.evaluateAgentBatch(batch)
+			if err != nil {
+				return errors.Trace(err)
+			}
+
+			if len(createFiles) > 0 || len(removeFiles) > 0 {
+				err = d.applyChanges(createFiles, removeFiles)
+				if err != nil {
+					return errors.Trace(err)
+				}
+			}
+		}
+	}
+}
+
+func (d *downstream) readAgentBatches(agentStdout io.Reader, batches chan []string) error {
+	var agentReader io.Reader = agentStdout
+	if d.config.DownstreamLimit > 0 {
+		agentReader = ratelimit.Reader(agentStdout, ratelimit.NewBucketWithRate(float64(d.config.DownstreamLimit), d.config.DownstreamLimit))
+	}
+
+	scanner := bufio.NewScanner(agentReader)
+	batch := make([]string, 0, 16)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line != agent.BatchAck {
+			batch = append(batch, line)
+			continue
+		}
+
+		select {
+		case <-d.interrupt:
+			return nil
+		case batches <- batch:
+		}
+
+		batch = make([]string, 0, 16)
+	}
+
+	if scanner.Err() != nil {
+		return scanner.Err()
+	}
+
+	return errors.New("agent stream closed")
+}
+
+func (d *downstream) evaluateAgentBatch(batch []string) ([]*fileInformation, map[string]*fileInformation, error) {
+	createFiles := make([]*fileInformation, 0, len(batch))
+	removeFiles := make(map[string]*fileInformation)
+
+	for _, line := range batch {
+		if strings.HasSuffix(line, agent.RecordSeparator+agent.RemoveRecord) {
+			d.evaluateAgentRemove(strings.TrimSuffix(line, agent.RecordSeparator+agent.RemoveRecord), removeFiles)
+			continue
+		}
+
+		_, err := d.evaluateFile(line, &createFiles, removeFiles)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
+
+	return createFiles, removeFiles, nil
+}
+
+func (d *downstream) evaluateAgentRemove(remotePath string, removeFiles map[string]*fileInformation) {
+	if len(remotePath) <= len(d.config.DestPath) {
+		return
+	}
+
+	name := remotePath[len(d.config.DestPath):]
+
+	d.config.fileIndex.fileMapMutex.Lock()
+	defer d.config.fileIndex.fileMapMutex.Unlock()
+
+	element := d.config.fileIndex.fileMap[name]
+	if element == nil || element.IsSymbolicLink {
+		return
+	}
+
+	removeFiles[name] = cloneFileInformation(element)
+
+	// Directory contents are removed with the directory
+	if element.IsDirectory {
+		for key, value := range d.config.fileIndex.fileMap {
+			if strings.HasPrefix(key, name+"/") && value.IsSymbolicLink == false {
+				removeFiles[key] = cloneFileInformation(value)
+			}
+		}
+	}
+}
+
+// getAgentStdout returns the output stream of the sync agent or nil if the agent is not running
+func (d *downstream) getAgentStdout() io.ReadCloser {
+	d.agentMutex.Lock()
+	defer d.agentMutex.Unlock()
+
+	return d.agentStdout
+}
+
+func (d *downstream) stopAgent() {
+	d.agentMutex.Lock()
+	defer d.agentMutex.Unlock()
+
+	if d.agentStdin != nil {
+		d.agentStdin.Close()
+		d.agentStdin = nil
+	}
+
+	if d.agentStdout != nil {
+		d.agentStdout.Close()
+		d.agentStdout = nil
+	}
+}
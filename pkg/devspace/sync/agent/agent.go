@@ -0,0 +1,226 @@
+package agent
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rjeczalik/notify"
+)
+
+// ReadyAck is written by the agent as soon as the watcher is set up
+const ReadyAck string = "START"
+
+// BatchAck is written by the agent after each batch of change records
+const BatchAck string = "DONE"
+
+// RemoveRecord is written instead of the stat information if a path was removed
+const RemoveRecord string = "REMOVE"
+
+// RecordSeparator separates the path from the stat information in a change record
+const RecordSeparator string = "///"
+
+// maxBatchSize is the maximum amount of paths the agent collects before it flushes a batch
+const maxBatchSize = 1000
+
+// Watcher watches a container path recursively and writes change records
+// in the same format as the downstream find command
+type Watcher struct {
+	// Path is the path that is watched as specified by the user (e.g. "." or "/app")
+	Path string
+
+	// Debounce is the time the watcher collects further events after the first change before it flushes a batch
+	Debounce time.Duration
+
+	// Checksum appends the md5 checksum of regular files to the change records
+	Checksum bool
+
+	absPath string
+	out     io.Writer
+}
+
+// NewWatcher creates a new watcher for the given path
+func NewWatcher(path string, out io.Writer) (*Watcher, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	absPath, err = filepath.EvalSymlinks(absPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Watcher{
+		Path:     path,
+		Debounce: time.Millisecond * 300,
+		absPath:  absPath,
+		out:      out,
+	}, nil
+}
+
+// Watch watches the path until stop is closed
+func (w *Watcher) Watch(stop <-chan bool) error {
+	events := make(chan notify.EventInfo, 5000) // High buffer size so we don't miss any events if there are a lot of changes
+
+	err := notify.Watch(w.absPath+"/...", events, notify.All)
+	if err != nil {
+		return err
+	}
+
+	defer notify.Stop(events)
+
+	_, err = fmt.Fprintln(w.out, ReadyAck)
+	if err != nil {
+		return err
+	}
+
+	changed := make(map[string]bool)
+
+	// The timer is started with the first change of a batch, so that continuous changes
+	// don't delay the batch forever
+	var flushTimer <-chan time.Time
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		case event := <-events:
+			changed[event.Path()] = true
+			if flushTimer == nil {
+				flushTimer = time.After(w.Debounce)
+			}
+			if len(changed) < maxBatchSize {
+				continue
+			}
+		case <-flushTimer:
+		}
+
+		err = w.flush(changed)
+		if err != nil {
+			return err
+		}
+
+		changed = make(map[string]bool)
+		flushTimer = nil
+	}
+}
+
+func (w *Watcher) flush(changed map[string]bool) error {
+	paths := make([]string, 0, len(changed))
+	for path := range changed {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+
+	records := make([]string, 0, len(paths))
+	reported := make(map[string]bool, len(paths))
+
+	for _, path := range paths {
+		if reported[path] || path == w.absPath || strings.HasPrefix(path, w.absPath+"/") == false {
+			continue
+		}
+
+		reported[path] = true
+
+		stat, err := os.Stat(path)
+		if err != nil {
+			records = append(records, w.rewritePath(path)+RecordSeparator+RemoveRecord)
+			continue
+		}
+
+		records = append(records, FormatRecord(w.rewritePath(path), stat))
+
+		// Directories that are moved into the watched path don't produce events for
+		// their contents, so we report everything that is inside
+		if stat.IsDir() {
+			_ = filepath.Walk(path, func(subPath string, info os.FileInfo, err error) error {
+				if err != nil || reported[subPath] {
+					return nil
+				}
+
+				reported[subPath] = true
+				records = append(records, w.formatRecord(subPath, info))
+				return nil
+			})
+		}
+	}
+
+	records = append(records, BatchAck)
+
+	_, err := io.WriteString(w.out, strings.Join(records, "\n")+"\n")
+	return err
+}
+
+// rewritePath converts an absolute path into the path find would print for the watched path
+func (w *Watcher) rewritePath(absPath string) string {
+	relativePath := filepath.ToSlash(absPath[len(w.absPath)+1:])
+	if strings.HasSuffix(w.Path, "/") {
+		return w.Path + relativePath
+	}
+
+	return w.Path + "/" + relativePath
+}
+
+// formatRecord formats the change record of the given absolute path and appends the checksum if enabled
+func (w *Watcher) formatRecord(absPath string, stat os.FileInfo) string {
+	record := FormatRecord(w.rewritePath(absPath), stat)
+	if w.Checksum == false || stat.Mode().IsRegular() == false {
+		return record
+	}
+
+	checksum, err := Checksum(absPath)
+	if err != nil {
+		return record
+	}
+
+	return record + "," + checksum
+}
+
+// Checksum returns the hex encoded md5 checksum of the given file in the same format as md5sum
+func Checksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+
+	defer f.Close()
+
+	hash := md5.New()
+	_, err = io.Copy(hash, f)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// FormatRecord formats the stat of a path in the same way as stat -c "%n///%s,%Y,%f,%a,%u,%g"
+func FormatRecord(path string, stat os.FileInfo) string {
+	rawMode, uid, gid := rawStat(stat)
+
+	return fmt.Sprintf("%s%s%d,%d,%x,%o,%d,%d", path, RecordSeparator, stat.Size(), stat.ModTime().Unix(), rawMode, uint32(stat.Mode().Perm()), uid, gid)
+}
+
+// fallbackMode builds the unix file mode from the go file mode if the raw mode is not available
+func fallbackMode(stat os.FileInfo) uint32 {
+	mode := uint32(stat.Mode().Perm())
+
+	switch {
+	case stat.Mode()&os.ModeSymlink != 0:
+		mode |= 0120000
+	case stat.IsDir():
+		mode |= 040000
+	default:
+		mode |= 0100000
+	}
+
+	return mode
+}
@@ -0,0 +1,79 @@
+package agent
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatcherContinuousChanges(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	reader, writer := io.Pipe()
+	defer reader.Close()
+
+	watcher, err := NewWatcher(dir, writer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	watcher.Debounce = time.Millisecond * 200
+
+	stop := make(chan bool)
+	defer close(stop)
+
+	go watcher.Watch(stop)
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(reader)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	select {
+	case line := <-lines:
+		if line != ReadyAck {
+			t.Fatalf("Expected %s, got %s", ReadyAck, line)
+		}
+	case <-time.After(time.Second * 10):
+		t.Fatal("Timeout waiting for the watcher to start")
+	}
+
+	// Write a file more often than the debounce time, the batch has to be flushed anyways
+	done := make(chan bool)
+	defer close(done)
+
+	go func() {
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			case <-time.After(time.Millisecond * 50):
+				ioutil.WriteFile(filepath.Join(dir, "test.log"), []byte(string(rune('a'+i%26))), 0644)
+			}
+		}
+	}()
+
+	timeout := time.After(time.Second * 5)
+	for {
+		select {
+		case line := <-lines:
+			if line == BatchAck {
+				return
+			}
+		case <-timeout:
+			t.Fatal("Batch was not flushed while the file was changed continuously")
+		}
+	}
+}
@@ -0,0 +1,16 @@
+// +build !windows
+
+package agent
+
+import (
+	"os"
+	"syscall"
+)
+
+func rawStat(stat os.FileInfo) (uint32, int, int) {
+	if sys, ok := stat.Sys().(*syscall.Stat_t); ok {
+		return uint32(sys.Mode), int(sys.Uid), int(sys.Gid)
+	}
+
+	return fallbackMode(stat), 0, 0
+}
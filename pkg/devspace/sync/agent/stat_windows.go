@@ -0,0 +1,9 @@
+// +build windows
+
+package agent
+
+import "os"
+
+func rawStat(stat os.FileInfo) (uint32, int, int) {
+	return fallbackMode(stat), 0, 0
+}
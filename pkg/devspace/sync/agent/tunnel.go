@@ -0,0 +1,382 @@
+package agent
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+)
+
+// Frame types of the reverse forwarding tunnel. Every frame consists of the type (1 byte),
+// the connection id (4 bytes), the payload length (4 bytes) and the payload. An ack frame carries
+// the number of data frames (4 bytes) the receiver has written to its connection
+const (
+	frameOpen byte = iota + 1
+	frameData
+	frameClose
+	frameAck
+)
+
+// maxFramePayload is the maximum amount of bytes a single data frame carries
+const maxFramePayload = 32 * 1024
+
+// connQueueSize is the number of data frames that are buffered for a connection. The sending side of a
+// connection only sends as many data frames as were acknowledged by the receiving side, so a slow connection
+// is throttled instead of blocking the other tunneled connections
+const connQueueSize = 256
+
+// ackBatchSize is the number of written data frames after which the receiving side sends an ack, even if
+// there are more frames queued
+const ackBatchSize = connQueueSize / 8
+
+// tunnel multiplexes several tcp connections over a single stream (e.g. the stdin and stdout of an exec stream)
+type tunnel struct {
+	in  io.Reader
+	out io.Writer
+
+	outMutex sync.Mutex
+
+	conns      map[uint32]*tunnelConn
+	connsMutex sync.Mutex
+}
+
+// tunnelConn is a tunneled connection. The connection is closed as soon as both sides are done writing
+type tunnelConn struct {
+	conn net.Conn
+
+	// queue holds the payloads that are written to conn by the writer goroutine of the connection
+	queue       chan []byte
+	queueClosed bool
+	overflow    bool
+
+	// credits holds a token for every data frame the other side is able to receive
+	credits chan bool
+	done    chan bool
+	removed bool
+
+	localDone  bool
+	remoteDone bool
+}
+
+// t.connsMutex needs to be locked before this function is called
+func (c *tunnelConn) closeQueue() {
+	if c.queueClosed == false {
+		c.queueClosed = true
+		close(c.queue)
+	}
+}
+
+// release stops a sender that waits for credits. t.connsMutex needs to be locked before this function is called
+func (c *tunnelConn) release() {
+	if c.removed == false {
+		c.removed = true
+		close(c.done)
+	}
+}
+
+func newTunnel(in io.Reader, out io.Writer) *tunnel {
+	return &tunnel{
+		in:    in,
+		out:   out,
+		conns: make(map[uint32]*tunnelConn),
+	}
+}
+
+// ServeTunnel accepts connections on the listener and tunnels them over out and in until in is closed.
+// ReadyAck is written to out before the first frame
+func ServeTunnel(listener net.Listener, in io.Reader, out io.Writer) error {
+	t := newTunnel(in, out)
+	defer t.closeAll()
+	defer listener.Close()
+
+	_, err := fmt.Fprintln(out, ReadyAck)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for id := uint32(1); ; id++ {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			c := t.add(id, conn)
+
+			err = t.writeFrame(frameOpen, id, nil)
+			if err != nil {
+				conn.Close()
+				return
+			}
+
+			go t.write(id, c, nil)
+			go t.pipe(id, c, conn)
+		}
+	}()
+
+	return t.readFrames(nil)
+}
+
+// ForwardTunnel reads the frames written by ServeTunnel from in and opens a new connection with dial
+// for every tunneled connection
+func ForwardTunnel(in io.Reader, out io.Writer, dial func() (net.Conn, error)) error {
+	t := newTunnel(in, out)
+	defer t.closeAll()
+
+	return t.readFrames(func(id uint32) {
+		// Dialing happens in the writer goroutine, so that a slow dial doesn't block the other connections
+		c := t.add(id, nil)
+		go t.write(id, c, dial)
+	})
+}
+
+// readFrames handles incoming frames until the stream is closed
+func (t *tunnel) readFrames(onOpen func(id uint32)) error {
+	header := make([]byte, 9)
+	for {
+		_, err := io.ReadFull(t.in, header)
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+
+			return err
+		}
+
+		id := binary.BigEndian.Uint32(header[1:5])
+		length := binary.BigEndian.Uint32(header[5:9])
+		if length > maxFramePayload {
+			return fmt.Errorf("Frame payload of connection %d exceeds maximum size (%d bytes)", id, length)
+		}
+
+		payload := make([]byte, length)
+		_, err = io.ReadFull(t.in, payload)
+		if err != nil {
+			return err
+		}
+
+		switch header[0] {
+		case frameOpen:
+			if onOpen == nil {
+				return fmt.Errorf("Unexpected open frame for connection %d", id)
+			}
+
+			onOpen(id)
+		case frameData:
+			t.connsMutex.Lock()
+			c := t.conns[id]
+			if c != nil && c.queueClosed == false {
+				select {
+				case c.queue <- payload:
+				default:
+					// The other side sent more frames than it had credits for
+					c.overflow = true
+					c.closeQueue()
+				}
+			}
+			t.connsMutex.Unlock()
+		case frameAck:
+			if length != 4 {
+				return fmt.Errorf("Invalid ack frame for connection %d", id)
+			}
+
+			t.connsMutex.Lock()
+			c := t.conns[id]
+			if c != nil {
+				c.addCredits(binary.BigEndian.Uint32(payload))
+			}
+			t.connsMutex.Unlock()
+		case frameClose:
+			t.connsMutex.Lock()
+			c := t.conns[id]
+			if c != nil {
+				c.closeQueue()
+			}
+			t.connsMutex.Unlock()
+		default:
+			return fmt.Errorf("Unknown frame type %d", header[0])
+		}
+	}
+}
+
+// write dials the connection if necessary and writes the queued payloads to it until the remote side
+// is done writing
+func (t *tunnel) write(id uint32, c *tunnelConn, dial func() (net.Conn, error)) {
+	conn := c.conn
+	if dial != nil {
+		var err error
+		conn, err = dial()
+		if err != nil {
+			t.writeFrame(frameClose, id, nil)
+			t.remove(id)
+			return
+		}
+
+		t.connsMutex.Lock()
+		if t.conns[id] != c {
+			// The tunnel was closed while dialing
+			t.connsMutex.Unlock()
+			conn.Close()
+			return
+		}
+
+		c.conn = conn
+		t.connsMutex.Unlock()
+
+		go t.pipe(id, c, conn)
+	}
+
+	failed := false
+	written := uint32(0)
+	for payload := range c.queue {
+		if failed == false {
+			_, err := conn.Write(payload)
+			if err != nil {
+				conn.Close()
+				failed = true
+			}
+		}
+
+		// Frames are acknowledged even if the connection failed, so that the other side doesn't wait for credits
+		written++
+		if written >= ackBatchSize || len(c.queue) == 0 {
+			t.writeAck(id, written)
+			written = 0
+		}
+	}
+
+	t.connsMutex.Lock()
+	defer t.connsMutex.Unlock()
+
+	c.remoteDone = true
+	if c.localDone || c.overflow {
+		conn.Close()
+		c.release()
+		if t.conns[id] == c {
+			delete(t.conns, id)
+		}
+	} else if closeWriter, ok := conn.(interface{ CloseWrite() error }); ok {
+		closeWriter.CloseWrite()
+	} else {
+		conn.Close()
+	}
+}
+
+// pipe copies everything that is read from conn as data frames into the tunnel. A data frame is only sent
+// if the other side acknowledged enough frames
+func (t *tunnel) pipe(id uint32, c *tunnelConn, conn net.Conn) {
+	buf := make([]byte, maxFramePayload)
+	for {
+		n, err := conn.Read(buf)
+		if n > 0 {
+			select {
+			case <-c.credits:
+			case <-c.done:
+				conn.Close()
+				return
+			}
+
+			writeErr := t.writeFrame(frameData, id, buf[:n])
+			if writeErr != nil {
+				conn.Close()
+				return
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	t.writeFrame(frameClose, id, nil)
+
+	t.connsMutex.Lock()
+	defer t.connsMutex.Unlock()
+
+	if t.conns[id] == c {
+		c.localDone = true
+		if c.remoteDone {
+			conn.Close()
+			c.release()
+			delete(t.conns, id)
+		}
+	}
+}
+
+// addCredits allows the sender to send the given number of further data frames. t.connsMutex needs to be
+// locked before this function is called
+func (c *tunnelConn) addCredits(count uint32) {
+	for i := uint32(0); i < count; i++ {
+		select {
+		case c.credits <- true:
+		default:
+			// The other side acknowledged more frames than were sent
+			return
+		}
+	}
+}
+
+func (t *tunnel) writeAck(id uint32, count uint32) error {
+	payload := make([]byte, 4)
+	binary.BigEndian.PutUint32(payload, count)
+
+	return t.writeFrame(frameAck, id, payload)
+}
+
+func (t *tunnel) writeFrame(frameType byte, id uint32, payload []byte) error {
+	frame := make([]byte, 9+len(payload))
+	frame[0] = frameType
+	binary.BigEndian.PutUint32(frame[1:5], id)
+	binary.BigEndian.PutUint32(frame[5:9], uint32(len(payload)))
+	copy(frame[9:], payload)
+
+	t.outMutex.Lock()
+	defer t.outMutex.Unlock()
+
+	_, err := t.out.Write(frame)
+	return err
+}
+
+func (t *tunnel) add(id uint32, conn net.Conn) *tunnelConn {
+	t.connsMutex.Lock()
+	defer t.connsMutex.Unlock()
+
+	c := &tunnelConn{
+		conn:    conn,
+		queue:   make(chan []byte, connQueueSize),
+		credits: make(chan bool, connQueueSize),
+		done:    make(chan bool),
+	}
+
+	// The other side is able to queue connQueueSize frames initially
+	c.addCredits(connQueueSize)
+
+	t.conns[id] = c
+	return c
+}
+
+func (t *tunnel) remove(id uint32) {
+	t.connsMutex.Lock()
+	defer t.connsMutex.Unlock()
+
+	if c := t.conns[id]; c != nil {
+		c.closeQueue()
+		c.release()
+		delete(t.conns, id)
+	}
+}
+
+func (t *tunnel) closeAll() {
+	t.connsMutex.Lock()
+	defer t.connsMutex.Unlock()
+
+	for id, c := range t.conns {
+		c.closeQueue()
+		c.release()
+		if c.conn != nil {
+			c.conn.Close()
+		}
+
+		delete(t.conns, id)
+	}
+}
@@ -0,0 +1,233 @@
+package agent
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// startEchoServer starts a local server that answers every connection with an echo of the request
+func startEchoServer(t *testing.T) net.Listener {
+	local, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		for {
+			conn, err := local.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+
+				request, _ := ioutil.ReadAll(conn)
+				conn.Write([]byte("echo " + string(request)))
+			}()
+		}
+	}()
+
+	return local
+}
+
+// startTestTunnel wires the agent and the local side together like the exec stream does and returns the
+// remote listener, the agent stdin and the result of ForwardTunnel
+func startTestTunnel(t *testing.T, dial func() (net.Conn, error)) (net.Listener, io.WriteCloser, chan error) {
+	remote, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	agentStdinReader, agentStdinWriter := io.Pipe()
+	agentStdoutReader, agentStdoutWriter := io.Pipe()
+
+	go func() {
+		ServeTunnel(remote, agentStdinReader, agentStdoutWriter)
+		agentStdoutWriter.Close()
+	}()
+
+	stdout := bufio.NewReader(agentStdoutReader)
+	line, err := stdout.ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(line) != ReadyAck {
+		t.Fatalf("Expected %s, got %s", ReadyAck, line)
+	}
+
+	forwardDone := make(chan error)
+	go func() {
+		forwardDone <- ForwardTunnel(stdout, agentStdinWriter, dial)
+	}()
+
+	return remote, agentStdinWriter, forwardDone
+}
+
+func request(t *testing.T, address, request string) {
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = conn.Write([]byte(request))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn.(*net.TCPConn).CloseWrite()
+
+	response, err := ioutil.ReadAll(conn)
+	conn.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(response) != "echo "+request {
+		t.Fatalf("Unexpected response of length %d for request of length %d", len(response), len(request))
+	}
+}
+
+func TestTunnel(t *testing.T) {
+	local := startEchoServer(t)
+	defer local.Close()
+
+	remote, agentStdin, forwardDone := startTestTunnel(t, func() (net.Conn, error) {
+		return net.Dial("tcp", local.Addr().String())
+	})
+
+	for _, r := range []string{"first", strings.Repeat("a", maxFramePayload*3)} {
+		request(t, remote.Addr().String(), r)
+	}
+
+	// Closing stdin stops the agent and the local side
+	agentStdin.Close()
+
+	err := <-forwardDone
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTunnelSlowConnection(t *testing.T) {
+	local := startEchoServer(t)
+	defer local.Close()
+
+	// The first connection is stuck while dialing
+	release := make(chan bool)
+	dials := make(chan bool, 10)
+	dialCount := int32(0)
+	remote, agentStdin, forwardDone := startTestTunnel(t, func() (net.Conn, error) {
+		dials <- true
+		if atomic.AddInt32(&dialCount, 1) == 1 {
+			<-release
+		}
+
+		return net.Dial("tcp", local.Addr().String())
+	})
+
+	slow, err := net.Dial("tcp", remote.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer slow.Close()
+
+	_, err = slow.Write([]byte("slow"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-dials:
+	case <-time.After(time.Second * 5):
+		t.Fatal("Timeout waiting for the first dial")
+	}
+
+	// Other connections are served while the first one is still dialing
+	done := make(chan bool)
+	go func() {
+		request(t, remote.Addr().String(), "fast")
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		t.Fatal("Connection was blocked by a slow connection")
+	}
+
+	close(release)
+	slow.(*net.TCPConn).CloseWrite()
+
+	response, err := ioutil.ReadAll(slow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(response) != "echo slow" {
+		t.Fatalf("Unexpected response %q", string(response))
+	}
+
+	agentStdin.Close()
+
+	err = <-forwardDone
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTunnelBackpressure(t *testing.T) {
+	// The local server sends more data than fits into the queue of a connection
+	size := connQueueSize * maxFramePayload * 2
+	local, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer local.Close()
+
+	go func() {
+		conn, err := local.Accept()
+		if err != nil {
+			return
+		}
+
+		defer conn.Close()
+		conn.Write([]byte(strings.Repeat("a", size)))
+	}()
+
+	remote, agentStdin, forwardDone := startTestTunnel(t, func() (net.Conn, error) {
+		return net.Dial("tcp", local.Addr().String())
+	})
+
+	conn, err := net.Dial("tcp", remote.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer conn.Close()
+
+	// The client doesn't read for a while, so that the tunnel has to throttle the local server
+	time.Sleep(time.Second)
+	conn.(*net.TCPConn).CloseWrite()
+
+	response, err := ioutil.ReadAll(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(response) != size {
+		t.Fatalf("Expected %d bytes, got %d", size, len(response))
+	}
+
+	agentStdin.Close()
+
+	err = <-forwardDone
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,151 @@
+package sync
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/sync/agent"
+	"github.com/juju/errors"
+)
+
+// checksumBatchSize is the maximum number of files that are hashed with a single md5sum call
+const checksumBatchSize = 100
+
+// checksumEntry is a cached container checksum together with the stat it was computed for
+type checksumEntry struct {
+	mtime    int64
+	size     int64
+	checksum string
+}
+
+// getChecksumCommand returns the command that prints the md5 checksums of the given container paths
+func getChecksumCommand(paths []string) string {
+	fileArguments := ""
+	for _, path := range paths {
+		fileArguments += "'" + strings.Replace(path, "'", "'\\''", -1) + "' "
+	}
+
+	return "md5sum " + fileArguments + "2>/dev/null; echo \"" + EndAck + "\"\n"
+}
+
+// parseChecksums parses the output of md5sum into a map of relative file names to checksums
+func parseChecksums(output, destPath string) map[string]string {
+	checksums := make(map[string]string)
+
+	for _, line := range strings.Split(output, "\n") {
+		// md5sum prints "CHECKSUM  PATH"
+		splitted := strings.SplitN(line, "  ", 2)
+		if len(splitted) != 2 || len(splitted[1]) <= len(destPath) || strings.HasPrefix(splitted[1], destPath) == false {
+			continue
+		}
+
+		checksums[splitted[1][len(destPath):]] = splitted[0]
+	}
+
+	return checksums
+}
+
+// updateChecksums refreshes the cached container checksums from the lines of the downstream find command. Only
+// files that are not excluded and whose mtime or size changed since the last poll are hashed, because hashing the
+// complete container path on every poll is too expensive
+func (d *downstream) updateChecksums(lines []string) error {
+	if d.checksums == nil {
+		d.checksums = make(map[string]*checksumEntry)
+	}
+
+	found := make(map[string]bool)
+	changed := make([]string, 0, 16)
+	for _, line := range lines {
+		fileInformation, err := parseFileInformation(line, d.config.DestPath)
+		if err != nil || fileInformation == nil || fileInformation.IsDirectory || fileInformation.IsSymbolicLink {
+			continue
+		}
+		if d.config.ignoreMatcher != nil && d.config.ignoreMatcher.MatchesPath(fileInformation.Name) {
+			continue
+		}
+		if d.config.downloadIgnoreMatcher != nil && d.config.downloadIgnoreMatcher.MatchesPath(fileInformation.Name) {
+			continue
+		}
+
+		found[fileInformation.Name] = true
+
+		entry := d.checksums[fileInformation.Name]
+		if entry != nil && entry.mtime == fileInformation.Mtime && entry.size == fileInformation.Size {
+			continue
+		}
+
+		d.checksums[fileInformation.Name] = &checksumEntry{
+			mtime: fileInformation.Mtime,
+			size:  fileInformation.Size,
+		}
+		changed = append(changed, d.config.DestPath+fileInformation.Name)
+	}
+
+	// Forget files that were removed in the container
+	for name := range d.checksums {
+		if found[name] == false {
+			delete(d.checksums, name)
+		}
+	}
+
+	for i := 0; i < len(changed); i += checksumBatchSize {
+		end := i + checksumBatchSize
+		if end > len(changed) {
+			end = len(changed)
+		}
+
+		_, err := d.stdinPipe.Write([]byte(getChecksumCommand(changed[i:end])))
+		if err != nil {
+			return errors.Trace(err)
+		}
+
+		output, err := readTill(EndAck, d.stdoutPipe)
+		if err != nil {
+			return errors.Trace(err)
+		}
+
+		for name, checksum := range parseChecksums(output, d.config.DestPath) {
+			if entry := d.checksums[name]; entry != nil {
+				entry.checksum = checksum
+			}
+		}
+	}
+
+	return nil
+}
+
+// getChecksum returns the cached container checksum of the file, if it was computed for the same mtime and size
+func (d *downstream) getChecksum(fileInformation *fileInformation) string {
+	entry := d.checksums[fileInformation.Name]
+	if entry == nil || entry.mtime != fileInformation.Mtime || entry.size != fileInformation.Size {
+		return ""
+	}
+
+	return entry.checksum
+}
+
+// localChecksum returns the md5 checksum of the local file at the given relative path
+func (s *SyncConfig) localChecksum(relativePath string) (string, error) {
+	return agent.Checksum(filepath.Join(s.WatchPath, relativePath))
+}
+
+// s.fileIndex needs to be locked before this function is called
+// compareChecksum compares the local file content with the last synced checksum. The second return value is
+// false if checksum mode is disabled or the checksum can't be compared, and the caller has to fall back to mtime and size
+func (s *SyncConfig) compareChecksum(relativePath string) (bool, bool) {
+	if s.Checksum == false {
+		return false, false
+	}
+
+	synced := s.fileIndex.fileMap[relativePath]
+	if synced == nil || synced.Checksum == "" {
+		return false, false
+	}
+
+	checksum, err := s.localChecksum(relativePath)
+	if err != nil {
+		return false, false
+	}
+
+	return checksum == synced.Checksum, true
+}
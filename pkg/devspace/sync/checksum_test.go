@@ -0,0 +1,116 @@
+package sync
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestParseChecksums(t *testing.T) {
+	output := "d41d8cd98f00b204e9800998ecf8427e  /app/empty\n" +
+		"5d41402abc4b2a76b9719d911017c592  /app/folder/hello\n" +
+		"5d41402abc4b2a76b9719d911017c592  /other/hello\n" +
+		"/app///5,1550000000,41ed,755,0,0\n" +
+		EndAck
+
+	checksums := parseChecksums(output, "/app")
+	if len(checksums) != 2 {
+		t.Fatalf("Expected 2 checksums, got %d: %v", len(checksums), checksums)
+	}
+	if checksums["/empty"] != "d41d8cd98f00b204e9800998ecf8427e" {
+		t.Fatalf("Wrong checksum for /empty: %s", checksums["/empty"])
+	}
+	if checksums["/folder/hello"] != "5d41402abc4b2a76b9719d911017c592" {
+		t.Fatalf("Wrong checksum for /folder/hello: %s", checksums["/folder/hello"])
+	}
+
+	fileInformation, err := parseFileInformation("/app/folder/hello///5,1550000000,81a4,644,0,0,5d41402abc4b2a76b9719d911017c592", "/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fileInformation.Checksum != "5d41402abc4b2a76b9719d911017c592" || fileInformation.Size != 5 {
+		t.Fatalf("Unexpected file information %#v", fileInformation)
+	}
+}
+
+func TestUpdateChecksums(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Skipping test on non linux platform")
+	}
+
+	remote, local, outside := initTestDirs(t)
+	defer os.RemoveAll(remote)
+	defer os.RemoveAll(local)
+	defer os.RemoveAll(outside)
+
+	for name, content := range map[string]string{"unchanged": "hello", "changed": "hello", "removed": "hello", "it's": "hello"} {
+		err := ioutil.WriteFile(filepath.Join(remote, name), []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Excluded files must not be hashed
+	err := os.Mkdir(filepath.Join(remote, "node_modules"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(filepath.Join(remote, "node_modules", "dep.js"), []byte("dep"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := createTestSyncClient(local, remote)
+	config.Checksum = true
+	config.ExcludePaths = []string{"node_modules/"}
+	config.fileIndex = newFileIndex()
+
+	err = config.initIgnoreParsers()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := &downstream{config: config}
+	err = d.startShell()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.stdinPipe.Close()
+
+	// The checksums are updated from the output of the regular poll
+	_, err = d.collectChanges(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.checksums) != 4 || d.checksums["/it's"] == nil || d.checksums["/it's"].checksum != "5d41402abc4b2a76b9719d911017c592" {
+		t.Fatalf("Unexpected checksums after first poll %#v", d.checksums)
+	}
+
+	// Unchanged files must not be hashed again
+	d.checksums["/unchanged"].checksum = "cached"
+
+	err = ioutil.WriteFile(filepath.Join(remote, "changed"), []byte("changed content"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Remove(filepath.Join(remote, "removed"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = d.collectChanges(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.checksums) != 3 || d.checksums["/removed"] != nil {
+		t.Fatalf("Expected removed file to be dropped from the cache %#v", d.checksums)
+	}
+	if d.checksums["/unchanged"].checksum != "cached" {
+		t.Fatalf("Expected /unchanged to not be hashed again, got %s", d.checksums["/unchanged"].checksum)
+	}
+	if d.checksums["/changed"].checksum == "5d41402abc4b2a76b9719d911017c592" || d.checksums["/changed"].checksum == "" {
+		t.Fatalf("Expected /changed to be hashed again, got %s", d.checksums["/changed"].checksum)
+	}
+}
@@ -0,0 +1,181 @@
+package sync
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	// CompressionAuto uses zstd if it is available in the container, gzip otherwise and falls back to plain tar
+	CompressionAuto = "auto"
+
+	// CompressionZstd compresses the transferred archives with zstd
+	CompressionZstd = "zstd"
+
+	// CompressionGzip compresses the transferred archives with gzip
+	CompressionGzip = "gzip"
+
+	// CompressionNone transfers plain tar archives
+	CompressionNone = "none"
+)
+
+// Compressions holds all supported compression settings
+var Compressions = []string{CompressionAuto, CompressionZstd, CompressionGzip, CompressionNone}
+
+func isValidCompression(compression string) bool {
+	for _, c := range Compressions {
+		if c == compression {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getCompressionProbeCommand returns the command that prints which compression tools are available in the container
+func getCompressionProbeCommand() string {
+	return "command -v zstd >/dev/null 2>&1 && echo \"" + CompressionZstd + "\"; command -v gzip >/dev/null 2>&1 && echo \"" + CompressionGzip + "\"; echo \"" + EndAck + "\"\n"
+}
+
+// selectCompression picks the compression for the configured setting based on the tools available in the container
+func selectCompression(configured string, available []string) string {
+	isAvailable := func(compression string) bool {
+		for _, a := range available {
+			if a == compression {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	switch configured {
+	case CompressionNone:
+		return CompressionNone
+	case CompressionGzip:
+		if isAvailable(CompressionGzip) {
+			return CompressionGzip
+		}
+	default:
+		if isAvailable(CompressionZstd) {
+			return CompressionZstd
+		}
+		if isAvailable(CompressionGzip) {
+			return CompressionGzip
+		}
+	}
+
+	return CompressionNone
+}
+
+// negotiateCompression checks which compression tools are available in the container and selects the
+// compression that is used for all archives transferred between the local machine and the container
+func (s *SyncConfig) negotiateCompression(stdin io.Writer, stdout io.Reader) error {
+	_, err := stdin.Write([]byte(getCompressionProbeCommand()))
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	output, err := readTill(EndAck, stdout)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	configured := s.Compression
+	if configured == "" {
+		configured = CompressionAuto
+	}
+
+	s.compression = selectCompression(configured, strings.Split(output, "\n"))
+	if configured != CompressionAuto && configured != s.compression {
+		s.Logf("[Sync] %s is not available in the container, falling back to %s", configured, s.compression)
+	} else if s.Verbose {
+		s.Logf("[Sync] Use %s compression", s.compression)
+	}
+
+	return nil
+}
+
+// transferCompression returns the negotiated compression. Archives are gzipped if no compression was negotiated
+func (s *SyncConfig) transferCompression() string {
+	if s.compression == "" {
+		return CompressionGzip
+	}
+
+	return s.compression
+}
+
+// getExtractCommand returns the shell command that extracts the archive in the container
+func getExtractCommand(compression, archive, destPath string) string {
+	switch compression {
+	case CompressionZstd:
+		return `zstd -dcq "` + archive + `" | tar xpf - -C '` + destPath + `/.'`
+	case CompressionNone:
+		return `tar xpf "` + archive + `" -C '` + destPath + `/.'`
+	default:
+		return `tar xzpf "` + archive + `" -C '` + destPath + `/.'`
+	}
+}
+
+// getArchiveCommand returns the shell command that writes the files listed in fileList into an archive in the container
+func getArchiveCommand(compression, archive, fileList string) string {
+	switch compression {
+	case CompressionZstd:
+		return `tar -cf - -T "` + fileList + `" | zstd -cq >"` + archive + `"`
+	case CompressionNone:
+		return `tar -cf "` + archive + `" -T "` + fileList + `"`
+	default:
+		return `tar -czf "` + archive + `" -T "` + fileList + `"`
+	}
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// newCompressWriter wraps the writer with the given compression. The returned writer has to be closed to flush the archive
+func newCompressWriter(writer io.Writer, compression string) (io.WriteCloser, error) {
+	switch compression {
+	case CompressionZstd:
+		return zstd.NewWriter(writer)
+	case CompressionNone:
+		return nopWriteCloser{writer}, nil
+	default:
+		return gzip.NewWriter(writer), nil
+	}
+}
+
+type zstdReadCloser struct {
+	*zstd.Decoder
+}
+
+func (z zstdReadCloser) Close() error {
+	z.Decoder.Close()
+	return nil
+}
+
+// newDecompressReader wraps the reader with the given compression
+func newDecompressReader(reader io.Reader, compression string) (io.ReadCloser, error) {
+	switch compression {
+	case CompressionZstd:
+		decoder, err := zstd.NewReader(reader)
+		if err != nil {
+			return nil, err
+		}
+
+		return zstdReadCloser{decoder}, nil
+	case CompressionNone:
+		return ioutil.NopCloser(reader), nil
+	default:
+		return gzip.NewReader(reader)
+	}
+}
@@ -0,0 +1,71 @@
+package sync
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+func TestSelectCompression(t *testing.T) {
+	testCases := []struct {
+		configured string
+		available  []string
+		expected   string
+	}{
+		{configured: CompressionAuto, available: []string{CompressionZstd, CompressionGzip}, expected: CompressionZstd},
+		{configured: CompressionAuto, available: []string{CompressionGzip}, expected: CompressionGzip},
+		{configured: CompressionAuto, available: []string{}, expected: CompressionNone},
+		{configured: CompressionZstd, available: []string{CompressionGzip}, expected: CompressionGzip},
+		{configured: CompressionGzip, available: []string{CompressionZstd}, expected: CompressionNone},
+		{configured: CompressionNone, available: []string{CompressionZstd, CompressionGzip}, expected: CompressionNone},
+	}
+
+	for _, testCase := range testCases {
+		selected := selectCompression(testCase.configured, testCase.available)
+		if selected != testCase.expected {
+			t.Fatalf("Compression %s with available %v: expected %s, got %s", testCase.configured, testCase.available, testCase.expected, selected)
+		}
+	}
+}
+
+func TestCompressRoundtrip(t *testing.T) {
+	content := bytes.Repeat([]byte("devspace sync "), 1000)
+
+	for _, compression := range []string{CompressionZstd, CompressionGzip, CompressionNone} {
+		buffer := &bytes.Buffer{}
+
+		writer, err := newCompressWriter(buffer, compression)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = writer.Write(content)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = writer.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if compression != CompressionNone && buffer.Len() >= len(content) {
+			t.Fatalf("Compression %s: expected compressed size < %d, got %d", compression, len(content), buffer.Len())
+		}
+
+		reader, err := newDecompressReader(buffer, compression)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decompressed, err := ioutil.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if bytes.Equal(decompressed, content) == false {
+			t.Fatalf("Compression %s: decompressed content differs", compression)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package sync
+
+import (
+	"io"
+	"os"
+	"strconv"
+)
+
+// ConflictPolicyNewestWins keeps the version with the newer modification time (default)
+const ConflictPolicyNewestWins = "newest-wins"
+
+// ConflictPolicyLocalWins always keeps the local version
+const ConflictPolicyLocalWins = "local-wins"
+
+// ConflictPolicyRemoteWins always keeps the container version
+const ConflictPolicyRemoteWins = "remote-wins"
+
+// ConflictPolicyKeepBoth keeps the container version and saves the local version as a .conflict copy
+const ConflictPolicyKeepBoth = "keep-both"
+
+// ConflictSuffix is appended to the local copy of a conflicting file with the keep-both policy
+const ConflictSuffix = ".conflict"
+
+// conflictExcludePaths exclude the conflict copies from the upload
+var conflictExcludePaths = []string{"*" + ConflictSuffix, "*" + ConflictSuffix + ".[0-9]*"}
+
+// ConflictPolicies holds all supported conflict policies
+var ConflictPolicies = []string{ConflictPolicyNewestWins, ConflictPolicyLocalWins, ConflictPolicyRemoteWins, ConflictPolicyKeepBoth}
+
+func isValidConflictPolicy(policy string) bool {
+	for _, p := range ConflictPolicies {
+		if p == policy {
+			return true
+		}
+	}
+
+	return false
+}
+
+// s.fileIndex needs to be locked before this function is called
+// A local file changed if it was never synced or if its mtime or size differ from the last synced state
+func hasChangedLocally(relativePath string, stat os.FileInfo, s *SyncConfig) bool {
+	if stat.IsDir() {
+		return false
+	}
+
+	synced := s.fileIndex.fileMap[relativePath]
+	if synced == nil {
+		return true
+	}
+	if synced.IsDirectory || synced.IsSymbolicLink {
+		return false
+	}
+	if unchanged, ok := s.compareChecksum(relativePath); ok {
+		return unchanged == false
+	}
+
+	return roundMtime(stat.ModTime()) != synced.Mtime || stat.Size() != synced.Size
+}
+
+// s.fileIndex needs to be locked before this function is called
+// A remote file changed if its mtime or size differ from the last synced state
+func hasChangedRemotely(remote *fileInformation, s *SyncConfig) bool {
+	synced := s.fileIndex.fileMap[remote.Name]
+	if synced == nil || synced.IsDirectory || synced.IsSymbolicLink || remote.IsDirectory {
+		return false
+	}
+	if s.Checksum && remote.Checksum != "" && synced.Checksum != "" {
+		return remote.Checksum != synced.Checksum
+	}
+
+	return remote.Mtime != synced.Mtime || remote.Size != synced.Size
+}
+
+// resolveConflict decides which version of a file that changed locally and in the container is kept
+// and reports the conflict. Returns true if the local version should be kept
+func (s *SyncConfig) resolveConflict(relativePath string, localMtime, remoteMtime int64) bool {
+	keepLocal := false
+
+	switch s.ConflictPolicy {
+	case ConflictPolicyLocalWins:
+		keepLocal = true
+	case ConflictPolicyRemoteWins, ConflictPolicyKeepBoth:
+		keepLocal = false
+	default:
+		keepLocal = localMtime >= remoteMtime
+	}
+
+	if keepLocal {
+		s.Logf("[Conflict] %s changed locally and in the container, keeping local version (policy: %s)", relativePath, s.conflictPolicy())
+	} else {
+		s.Logf("[Conflict] %s changed locally and in the container, keeping container version (policy: %s)", relativePath, s.conflictPolicy())
+	}
+
+	return keepLocal
+}
+
+// resolveRemoveConflict decides if a file that was removed on one side and changed on the other side
+// should be removed and reports the conflict. Only the local-wins or remote-wins policy remove a changed file
+func (s *SyncConfig) resolveRemoveConflict(relativePath string, removedRemotely bool) bool {
+	remove := false
+	if removedRemotely {
+		remove = s.ConflictPolicy == ConflictPolicyRemoteWins
+	} else {
+		remove = s.ConflictPolicy == ConflictPolicyLocalWins
+	}
+
+	side, otherSide := "locally", "in the container"
+	if removedRemotely {
+		side, otherSide = otherSide, side
+	}
+
+	if remove {
+		s.Logf("[Conflict] %s was removed %s and changed %s, removing it (policy: %s)", relativePath, side, otherSide, s.conflictPolicy())
+	} else {
+		s.Logf("[Conflict] %s was removed %s and changed %s, keeping the changed version (policy: %s)", relativePath, side, otherSide, s.conflictPolicy())
+	}
+
+	return remove
+}
+
+func (s *SyncConfig) conflictPolicy() string {
+	if s.ConflictPolicy == "" {
+		return ConflictPolicyNewestWins
+	}
+
+	return s.ConflictPolicy
+}
+
+// maxPendingUploads is the maximum number of files that are queued for upload by conflict resolution
+const maxPendingUploads = 5000
+
+// queueUpload queues a local file for upload without blocking the caller, which usually holds the fileIndex lock.
+// The queue is drained by the upstream main loop and doesn't accept new files once the sync is stopped
+func (s *SyncConfig) queueUpload(relativePath string, stat os.FileInfo) {
+	if s.upstream == nil || s.upstream.interrupt == nil {
+		return
+	}
+
+	select {
+	case <-s.upstream.interrupt:
+		return
+	default:
+	}
+
+	s.upstream.pendingUploadsMutex.Lock()
+	defer s.upstream.pendingUploadsMutex.Unlock()
+
+	if s.upstream.pendingUploads == nil {
+		s.upstream.pendingUploads = make(map[string]*fileInformation)
+	}
+	if _, ok := s.upstream.pendingUploads[relativePath]; ok == false && len(s.upstream.pendingUploads) >= maxPendingUploads {
+		s.Logf("[Conflict] Skip upload of %s, because too many uploads are pending", relativePath)
+		return
+	}
+
+	s.upstream.pendingUploads[relativePath] = &fileInformation{
+		Name:        relativePath,
+		Mtime:       roundMtime(stat.ModTime()),
+		Size:        stat.Size(),
+		IsDirectory: false,
+	}
+}
+
+// takePendingUploads returns and clears the files queued by queueUpload
+func (u *upstream) takePendingUploads() []*fileInformation {
+	u.pendingUploadsMutex.Lock()
+	defer u.pendingUploadsMutex.Unlock()
+
+	changes := make([]*fileInformation, 0, len(u.pendingUploads))
+	for _, change := range u.pendingUploads {
+		changes = append(changes, change)
+	}
+
+	u.pendingUploads = nil
+	return changes
+}
+
+// saveConflictCopy copies the local file next to the original with the conflict suffix and returns the new path
+func saveConflictCopy(absFilepath string, stat os.FileInfo) (string, error) {
+	conflictPath := absFilepath + ConflictSuffix
+	for i := 1; ; i++ {
+		_, err := os.Stat(conflictPath)
+		if os.IsNotExist(err) {
+			break
+		}
+
+		conflictPath = absFilepath + ConflictSuffix + "." + strconv.Itoa(i)
+	}
+
+	source, err := os.Open(absFilepath)
+	if err != nil {
+		return "", err
+	}
+
+	defer source.Close()
+
+	target, err := os.OpenFile(conflictPath, os.O_CREATE|os.O_WRONLY|os.O_EXCL, stat.Mode())
+	if err != nil {
+		return "", err
+	}
+
+	_, err = io.Copy(target, source)
+	if err != nil {
+		target.Close()
+		return "", err
+	}
+
+	err = target.Close()
+	if err != nil {
+		return "", err
+	}
+
+	return conflictPath, os.Chtimes(conflictPath, stat.ModTime(), stat.ModTime())
+}
+
+// keepConflictCopy saves the local version of a file before it is overridden by the container version
+// if the keep-both policy is used. Returns false if the local version could not be saved
+func (s *SyncConfig) keepConflictCopy(relativePath, absFilepath string, stat os.FileInfo) bool {
+	if s.ConflictPolicy != ConflictPolicyKeepBoth {
+		return true
+	}
+
+	conflictPath, err := saveConflictCopy(absFilepath, stat)
+	if err != nil {
+		s.Logf("[Conflict] Couldn't save local version of %s, keeping it instead: %v", relativePath, err)
+		return false
+	}
+
+	s.Logf("[Conflict] Saved local version of %s as %s", relativePath, getRelativeFromFullPath(conflictPath, s.WatchPath))
+	return true
+}
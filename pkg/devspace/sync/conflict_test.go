@@ -0,0 +1,277 @@
+package sync
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/devspace-cloud/devspace/pkg/util/log"
+)
+
+func TestResolveConflict(t *testing.T) {
+	testCases := []struct {
+		policy      string
+		localMtime  int64
+		remoteMtime int64
+		keepLocal   bool
+	}{
+		{policy: "", localMtime: 20, remoteMtime: 10, keepLocal: true},
+		{policy: "", localMtime: 10, remoteMtime: 20, keepLocal: false},
+		{policy: ConflictPolicyNewestWins, localMtime: 10, remoteMtime: 20, keepLocal: false},
+		{policy: ConflictPolicyLocalWins, localMtime: 10, remoteMtime: 20, keepLocal: true},
+		{policy: ConflictPolicyRemoteWins, localMtime: 20, remoteMtime: 10, keepLocal: false},
+		{policy: ConflictPolicyKeepBoth, localMtime: 20, remoteMtime: 10, keepLocal: false},
+	}
+
+	for _, testCase := range testCases {
+		s := &SyncConfig{ConflictPolicy: testCase.policy, silent: true}
+
+		keepLocal := s.resolveConflict("/file", testCase.localMtime, testCase.remoteMtime)
+		if keepLocal != testCase.keepLocal {
+			t.Fatalf("Policy %q with local mtime %d and remote mtime %d: expected keepLocal %v, got %v", testCase.policy, testCase.localMtime, testCase.remoteMtime, testCase.keepLocal, keepLocal)
+		}
+	}
+}
+
+func TestUntarConflict(t *testing.T) {
+	testCases := []struct {
+		policy          string
+		expectedContent string
+		expectConflict  bool
+	}{
+		{policy: ConflictPolicyNewestWins, expectedContent: "local"},
+		{policy: ConflictPolicyLocalWins, expectedContent: "local"},
+		{policy: ConflictPolicyRemoteWins, expectedContent: "remote"},
+		{policy: ConflictPolicyKeepBoth, expectedContent: "remote", expectConflict: true},
+	}
+
+	for _, testCase := range testCases {
+		local, err := ioutil.TempDir("", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer os.RemoveAll(local)
+
+		syncedTime := time.Now().Add(-time.Hour).Round(time.Second)
+		remoteTime := syncedTime.Add(time.Minute)
+		localTime := syncedTime.Add(time.Minute * 2)
+
+		// Local file changed since the last sync
+		localFile := filepath.Join(local, "file")
+		err = ioutil.WriteFile(localFile, []byte("local"), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.Chtimes(localFile, localTime, localTime)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		s := &SyncConfig{
+			WatchPath:      local,
+			DestPath:       "/app",
+			ConflictPolicy: testCase.policy,
+			fileIndex:      newFileIndex(),
+			silent:         true,
+		}
+		s.fileIndex.fileMap["/file"] = &fileInformation{
+			Name:  "/file",
+			Mtime: syncedTime.Unix(),
+			Size:  int64(len("synced")),
+		}
+
+		// Remote file changed since the last sync
+		err = untarAll(createTestArchive(t, "app/file", "remote", remoteTime), local, "/app", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		content, err := ioutil.ReadFile(localFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != testCase.expectedContent {
+			t.Fatalf("Policy %s: expected content %s, got %s", testCase.policy, testCase.expectedContent, string(content))
+		}
+
+		content, err = ioutil.ReadFile(localFile + ConflictSuffix)
+		if testCase.expectConflict {
+			if err != nil || string(content) != "local" {
+				t.Fatalf("Policy %s: expected conflict copy with local content, got %s (%v)", testCase.policy, string(content), err)
+			}
+		} else if err == nil {
+			t.Fatalf("Policy %s: unexpected conflict copy", testCase.policy)
+		}
+	}
+}
+
+func createTestArchive(t *testing.T, name, content string, mtime time.Time) *bytes.Buffer {
+	buffer := &bytes.Buffer{}
+	gw := gzip.NewWriter(buffer)
+	tw := tar.NewWriter(gw)
+
+	err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(content)),
+		ModTime: mtime,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = tw.Write([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tw.Close()
+	gw.Close()
+
+	return buffer
+}
+
+func TestConflictCopiesAreNotUploaded(t *testing.T) {
+	s := &SyncConfig{UploadExcludePaths: []string{"/node_modules"}}
+	err := s.initIgnoreParsers()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for path, excluded := range map[string]bool{
+		"/file":                    false,
+		"/folder/file.go":          false,
+		"/file" + ConflictSuffix:   true,
+		"/folder/file.go.conflict": true,
+		"/file.conflict.2":         true,
+		"/file.conflicts":          false,
+		"/node_modules":            true,
+	} {
+		if s.uploadIgnoreMatcher.MatchesPath(path) != excluded {
+			t.Fatalf("Expected upload exclude of %s to be %v", path, excluded)
+		}
+	}
+}
+
+func TestQueueUpload(t *testing.T) {
+	s := &SyncConfig{silent: true}
+	s.upstream = &upstream{config: s, interrupt: make(chan bool, 1)}
+
+	stat, err := os.Stat(os.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.queueUpload("/file", stat)
+	s.queueUpload("/file", stat)
+
+	changes := s.upstream.takePendingUploads()
+	if len(changes) != 1 || changes[0].Name != "/file" {
+		t.Fatalf("Expected one pending upload, got %#v", changes)
+	}
+
+	// Nothing is queued after the sync was stopped
+	close(s.upstream.interrupt)
+	s.queueUpload("/file", stat)
+
+	changes = s.upstream.takePendingUploads()
+	if len(changes) != 0 {
+		t.Fatalf("Expected no pending uploads after stop, got %#v", changes)
+	}
+}
+
+func TestConflictPolicyValidation(t *testing.T) {
+	testCases := []struct {
+		policy         string
+		uploadOnly     bool
+		downloadOnly   bool
+		expectedPolicy string
+		expectError    bool
+	}{
+		{policy: "", uploadOnly: true, expectedPolicy: ConflictPolicyLocalWins},
+		{policy: "", downloadOnly: true, expectedPolicy: ConflictPolicyRemoteWins},
+		{policy: ConflictPolicyKeepBoth, downloadOnly: true, expectedPolicy: ConflictPolicyKeepBoth},
+		{policy: ConflictPolicyLocalWins, uploadOnly: true, expectedPolicy: ConflictPolicyLocalWins},
+		{policy: ConflictPolicyRemoteWins, uploadOnly: true, expectError: true},
+		{policy: ConflictPolicyLocalWins, downloadOnly: true, expectError: true},
+		{policy: "unknown", uploadOnly: true, expectError: true},
+		{policy: "unknown", expectError: true},
+	}
+
+	for _, testCase := range testCases {
+		s := &SyncConfig{
+			WatchPath:      os.TempDir(),
+			ConflictPolicy: testCase.policy,
+			UploadOnly:     testCase.uploadOnly,
+			DownloadOnly:   testCase.downloadOnly,
+			CustomLog:      log.Discard,
+			silent:         true,
+		}
+
+		err := s.setup()
+		if testCase.expectError {
+			if err == nil {
+				t.Fatalf("Expected error for policy %q (upload only: %v, download only: %v)", testCase.policy, testCase.uploadOnly, testCase.downloadOnly)
+			}
+
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.ConflictPolicy != testCase.expectedPolicy {
+			t.Fatalf("Expected policy %s, got %s", testCase.expectedPolicy, s.ConflictPolicy)
+		}
+	}
+}
+
+func TestGetRemoteChanges(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Skipping test on non linux platform")
+	}
+
+	remote, local, outside := initTestDirs(t)
+	defer os.RemoveAll(remote)
+	defer os.RemoveAll(local)
+	defer os.RemoveAll(outside)
+
+	for _, name := range []string{"it's", "unchanged"} {
+		err := ioutil.WriteFile(filepath.Join(remote, name), []byte("remote"), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stat, err := os.Stat(filepath.Join(remote, "unchanged"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := createTestSyncClient(local, remote)
+	s.fileIndex = newFileIndex()
+	s.fileIndex.fileMap["/it's"] = &fileInformation{Name: "/it's", Mtime: 1, Size: 1}
+	s.fileIndex.fileMap["/unchanged"] = &fileInformation{Name: "/unchanged", Mtime: stat.ModTime().Unix(), Size: stat.Size()}
+
+	u := &upstream{config: s}
+	err = u.startShell()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer u.stdinPipe.Close()
+
+	// File names with quotes have to be escaped for sh
+	remoteChanges, err := u.getRemoteChanges([]*fileInformation{{Name: "/it's"}, {Name: "/unchanged"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(remoteChanges) != 1 || remoteChanges["/it's"] == nil {
+		t.Fatalf("Expected only /it's to be changed remotely, got %#v", remoteChanges)
+	}
+}
@@ -0,0 +1,206 @@
+package sync
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/versions/latest"
+	"github.com/devspace-cloud/devspace/pkg/devspace/kubectl"
+	"github.com/juju/errors"
+	k8sv1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// CopyFromContainer copies a container file or folder to a local path. The contents of a folder are copied
+// into the local path. A file is written to the local path or into it, if the local path is an existing folder
+func CopyFromContainer(DevSpaceConfig *latest.Config, Kubectl kubernetes.Interface, Pod *k8sv1.Pod, Container *k8sv1.Container, ContainerPath, LocalPath string, ExcludePaths []string) error {
+	return copyFromContainerTestable(DevSpaceConfig, Kubectl, Pod, Container, ContainerPath, LocalPath, ExcludePaths, false)
+}
+
+func copyFromContainerTestable(DevSpaceConfig *latest.Config, Kubectl kubernetes.Interface, Pod *k8sv1.Pod, Container *k8sv1.Container, ContainerPath, LocalPath string, ExcludePaths []string, testing bool) error {
+	ContainerPath = strings.TrimSuffix(ContainerPath, "/")
+	if ContainerPath == "" {
+		ContainerPath = "/"
+	}
+
+	isDirectory, err := isContainerDirectory(DevSpaceConfig, Kubectl, Pod, Container, ContainerPath, testing)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	LocalPath, err = filepath.Abs(LocalPath)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	localStat, err := os.Stat(LocalPath)
+	if err != nil && os.IsNotExist(err) == false {
+		return errors.Trace(err)
+	}
+
+	localIsDirectory := localStat != nil && localStat.IsDir()
+	if isDirectory == false && localIsDirectory {
+		LocalPath = filepath.Join(LocalPath, path.Base(ContainerPath))
+	}
+
+	// We download into a temporary folder next to the target first, so that we can simply move the
+	// downloaded files to the target afterwards
+	err = os.MkdirAll(filepath.Dir(LocalPath), 0755)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	tempDir, err := ioutil.TempDir(filepath.Dir(LocalPath), ".devspace-cp-")
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	defer os.RemoveAll(tempDir)
+
+	// A single file is downloaded directly instead of syncing its parent folder
+	if isDirectory == false {
+		return copyFileFromContainer(DevSpaceConfig, Kubectl, Pod, Container, ContainerPath, tempDir, LocalPath, testing)
+	}
+
+	s := &SyncConfig{
+		DevSpaceConfig: DevSpaceConfig,
+		Kubectl:        Kubectl,
+		Pod:            Pod,
+		Container:      Container,
+		WatchPath:      tempDir,
+		DestPath:       ContainerPath,
+		ExcludePaths:   ExcludePaths,
+		DownloadOnly:   true,
+		silent:         true,
+		testing:        testing,
+	}
+
+	_, err = s.RunOnce()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if localStat == nil {
+		err = os.Rename(s.WatchPath, LocalPath)
+		if err != nil {
+			return errors.Trace(err)
+		}
+
+		return errors.Trace(os.Chmod(LocalPath, 0755))
+	}
+
+	return errors.Trace(moveInto(s.WatchPath, LocalPath))
+}
+
+// copyFileFromContainer archives a single container file with tar and writes it to the local path. The file is
+// written into the temporary folder first and moved to the local path afterwards
+func copyFileFromContainer(DevSpaceConfig *latest.Config, Kubectl kubernetes.Interface, Pod *k8sv1.Pod, Container *k8sv1.Container, ContainerPath, tempDir, LocalPath string, testing bool) error {
+	// The ./ prefix prevents tar from parsing file names that start with a dash as option
+	command := []string{"tar", "-cf", "-", "-C", path.Dir(ContainerPath), "./" + path.Base(ContainerPath)}
+
+	stdoutReader, stdoutWriter := io.Pipe()
+	stderr := &bytes.Buffer{}
+	done := make(chan bool)
+	go func() {
+		defer close(done)
+
+		var err error
+		if testing == false {
+			err = kubectl.ExecStream(DevSpaceConfig, Kubectl, Pod, Container.Name, command, false, nil, stdoutWriter, stderr)
+		} else {
+			cmd := exec.Command(command[0], command[1:]...)
+			cmd.Stdout = stdoutWriter
+			cmd.Stderr = stderr
+			err = cmd.Run()
+		}
+
+		stdoutWriter.CloseWithError(err)
+	}()
+
+	defer stdoutReader.Close()
+
+	tarReader := tar.NewReader(stdoutReader)
+	header, err := tarReader.Next()
+	if err != nil {
+		stdoutReader.Close()
+		<-done
+
+		return errors.Errorf("Couldn't download %s: %v %s", ContainerPath, err, strings.TrimSpace(stderr.String()))
+	}
+	if header.FileInfo().Mode().IsRegular() == false {
+		return errors.Errorf("Couldn't download %s: not a regular file", ContainerPath)
+	}
+
+	downloaded := filepath.Join(tempDir, path.Base(ContainerPath))
+	f, err := os.OpenFile(downloaded, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, getLocalFileMode(header.Mode))
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	_, err = io.Copy(f, tarReader)
+	f.Close()
+	if err != nil {
+		return errors.Errorf("Couldn't download %s: %v", ContainerPath, err)
+	}
+
+	err = os.Chtimes(downloaded, header.ModTime, header.ModTime)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	return errors.Trace(os.Rename(downloaded, LocalPath))
+}
+
+// isContainerDirectory checks if the container path is a folder and returns an error if it does not exist
+func isContainerDirectory(DevSpaceConfig *latest.Config, Kubectl kubernetes.Interface, Pod *k8sv1.Pod, Container *k8sv1.Container, ContainerPath string, testing bool) (bool, error) {
+	quotedPath := "'" + strings.Replace(ContainerPath, "'", "'\\''", -1) + "'"
+	command := []string{"sh", "-c", "if [ -d " + quotedPath + " ]; then echo directory; elif [ -e " + quotedPath + " ]; then echo file; fi"}
+
+	var stdout []byte
+	var err error
+	if testing == false {
+		stdout, _, err = kubectl.ExecBuffered(DevSpaceConfig, Kubectl, Pod, Container.Name, command)
+	} else {
+		stdout, err = exec.Command(command[0], command[1:]...).Output()
+	}
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+
+	switch strings.TrimSpace(string(stdout)) {
+	case "directory":
+		return true, nil
+	case "file":
+		return false, nil
+	}
+
+	return false, errors.Errorf("%s does not exist within the container", ContainerPath)
+}
+
+// moveInto moves all files and folders from source into target and overwrites existing files
+func moveInto(source, target string) error {
+	return filepath.Walk(source, func(sourcePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(source, sourcePath)
+		if err != nil || relativePath == "." {
+			return err
+		}
+
+		targetPath := filepath.Join(target, relativePath)
+		if info.IsDir() {
+			return os.MkdirAll(targetPath, info.Mode()|0700)
+		}
+
+		return os.Rename(sourcePath, targetPath)
+	})
+}
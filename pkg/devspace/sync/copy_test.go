@@ -0,0 +1,92 @@
+package sync
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestCopyFromContainerTestable(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Skipping test on non linux platform")
+	}
+
+	remote, local, outside := initTestDirs(t)
+	defer os.RemoveAll(remote)
+	defer os.RemoveAll(local)
+	defer os.RemoveAll(outside)
+	defer useTestLogdir(outside)()
+
+	files := map[string]string{
+		"reports/result.xml":       "result",
+		"reports/sub/coverage.txt": "coverage",
+		"reports/debug.log":        "log",
+		"core.dump":                "dump",
+		"!core[1].dump":            "dump 1",
+	}
+	for name, content := range files {
+		err := os.MkdirAll(filepath.Join(remote, filepath.Dir(name)), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = ioutil.WriteFile(filepath.Join(remote, name), []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Copy a folder into a new local folder
+	err := copyFromContainerTestable(nil, nil, nil, nil, filepath.Join(remote, "reports"), filepath.Join(local, "reports"), []string{"*.log"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Copy a single file into an existing local folder
+	err = copyFromContainerTestable(nil, nil, nil, nil, filepath.Join(remote, "core.dump"), local, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Copy a single file whose name contains gitignore characters to a new local file
+	err = copyFromContainerTestable(nil, nil, nil, nil, filepath.Join(remote, "!core[1].dump"), filepath.Join(local, "core1.dump"), nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"reports/result.xml":       "result",
+		"reports/sub/coverage.txt": "coverage",
+		"core.dump":                "dump",
+		"core1.dump":               "dump 1",
+	}
+	for name, content := range expected {
+		data, err := ioutil.ReadFile(filepath.Join(local, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != content {
+			t.Fatalf("Expected %s to be %q, got %q", name, content, string(data))
+		}
+	}
+
+	_, err = os.Stat(filepath.Join(local, "reports", "debug.log"))
+	if os.IsNotExist(err) == false {
+		t.Fatal("Expected excluded file reports/debug.log to not be copied")
+	}
+
+	entries, err := ioutil.ReadDir(local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected only core.dump, core1.dump and reports in %s, got %d entries", local, len(entries))
+	}
+
+	err = copyFromContainerTestable(nil, nil, nil, nil, filepath.Join(remote, "missing"), local, nil, true)
+	if err == nil {
+		t.Fatal("Expected error for a missing container path")
+	}
+}
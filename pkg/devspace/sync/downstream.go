@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/juju/errors"
@@ -26,6 +27,13 @@ type downstream struct {
 	stdinPipe  io.WriteCloser
 	stdoutPipe io.ReadCloser
 	stderrPipe io.ReadCloser
+
+	agentStdin  io.WriteCloser
+	agentStdout io.ReadCloser
+	agentMutex  sync.Mutex
+
+	// checksums caches the container checksums in checksum mode
+	checksums map[string]*checksumEntry
 }
 
 func (d *downstream) start() error {
@@ -36,6 +44,13 @@ func (d *downstream) start() error {
 		return errors.Trace(err)
 	}
 
+	if d.config.UseAgent && d.config.UploadOnly == false {
+		err = d.startAgent()
+		if err != nil {
+			d.config.Logf("[Downstream] Couldn't start sync agent, falling back to polling: %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -103,6 +118,14 @@ func (d *downstream) populateFileMap() error {
 }
 
 func (d *downstream) mainLoop() error {
+	if d.getAgentStdout() != nil {
+		return d.agentLoop()
+	}
+
+	return d.pollLoop()
+}
+
+func (d *downstream) pollLoop() error {
 	lastAmountChanges := 0
 
 	for {
@@ -144,12 +167,7 @@ func (d *downstream) cloneFileMap() map[string]*fileInformation {
 			continue
 		}
 
-		mapClone[key] = &fileInformation{
-			Name:        value.Name,
-			Size:        value.Size,
-			Mtime:       value.Mtime,
-			IsDirectory: value.IsDirectory,
-		}
+		mapClone[key] = cloneFileInformation(value)
 	}
 
 	return mapClone
@@ -159,6 +177,17 @@ func (d *downstream) collectChanges(removeFiles map[string]*fileInformation) ([]
 	createFiles := make([]*fileInformation, 0, 128)
 	destPathFound := false
 
+	// In checksum mode the lines are evaluated after the checksums of the changed files were updated
+	lines := []string{}
+	evaluate := func(line string) (bool, error) {
+		if d.config.Checksum {
+			lines = append(lines, line)
+			return false, nil
+		}
+
+		return d.evaluateFile(line, &createFiles, removeFiles)
+	}
+
 	// Write find command to stdin pipe
 	cmd := getFindCommand(d.config.DestPath)
 	_, err := d.stdinPipe.Write([]byte(cmd))
@@ -195,7 +224,7 @@ func (d *downstream) collectChanges(removeFiles map[string]*fileInformation) ([]
 			return nil, errors.Trace(err)
 		}
 
-		done, overlap, err = d.parseLines(string(buf), overlap, &createFiles, removeFiles, &destPathFound)
+		done, overlap, err = d.parseLines(string(buf), overlap, evaluate, &destPathFound)
 		if err != nil {
 			if _, ok := err.(parsingError); ok {
 				time.Sleep(time.Second * 4)
@@ -207,6 +236,23 @@ func (d *downstream) collectChanges(removeFiles map[string]*fileInformation) ([]
 		}
 	}
 
+	if d.config.Checksum {
+		err = d.updateChecksums(lines)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+
+		for _, line := range lines {
+			destPath, err := d.evaluateFile(line, &createFiles, removeFiles)
+			if destPath {
+				destPathFound = true
+			}
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+	}
+
 	if destPathFound == false {
 		return nil, errors.New("DestPath not found, find command did not execute correctly")
 	}
@@ -214,7 +260,7 @@ func (d *downstream) collectChanges(removeFiles map[string]*fileInformation) ([]
 	return createFiles, nil
 }
 
-func (d *downstream) parseLines(buffer, overlap string, createFiles *[]*fileInformation, removeFiles map[string]*fileInformation, destPathFound *bool) (bool, string, error) {
+func (d *downstream) parseLines(buffer, overlap string, evaluate func(line string) (bool, error), destPathFound *bool) (bool, string, error) {
 	lines := strings.Split(buffer, "\n")
 
 	for index, element := range lines {
@@ -239,7 +285,7 @@ func (d *downstream) parseLines(buffer, overlap string, createFiles *[]*fileInfo
 				msg: "Parsing Error",
 			}
 		} else if line != "" {
-			destPath, err := d.evaluateFile(line, createFiles, removeFiles)
+			destPath, err := evaluate(line)
 			if destPath {
 				*destPathFound = destPath
 			}
@@ -272,6 +318,10 @@ func (d *downstream) evaluateFile(fileline string, createFiles *[]*fileInformati
 	// File found don't delete it
 	delete(removeFiles, fileInformation.Name)
 
+	if fileInformation.Checksum == "" && d.checksums != nil {
+		fileInformation.Checksum = d.getChecksum(fileInformation)
+	}
+
 	// Update mode, gid & uid if exists
 	if d.config.fileIndex.fileMap[fileInformation.Name] != nil {
 		d.config.fileIndex.fileMap[fileInformation.Name].RemoteMode = fileInformation.RemoteMode
@@ -323,6 +373,7 @@ func (d *downstream) applyChanges(createFiles []*fileInformation, removeFiles ma
 	d.removeFilesAndFolders(removeFiles)
 	d.createFolders(createFolders)
 
+	downloadedBytes := int64(0)
 	if len(downloadFiles) > 0 {
 		f, err := os.Open(tempDownloadpath)
 		if err != nil {
@@ -331,6 +382,13 @@ func (d *downstream) applyChanges(createFiles []*fileInformation, removeFiles ma
 
 		defer f.Close()
 
+		stat, err := f.Stat()
+		if err != nil {
+			return errors.Trace(err)
+		}
+
+		downloadedBytes = stat.Size()
+
 		// Untaring all downloaded files to the right location
 		// this can be a lengthy process when we downloaded a lot of files
 		err = untarAll(f, d.config.WatchPath, d.config.DestPath, d.config)
@@ -340,6 +398,7 @@ func (d *downstream) applyChanges(createFiles []*fileInformation, removeFiles ma
 	}
 
 	d.config.Logf("[Downstream] Successfully processed %d change(s)", len(createFiles)+len(removeFiles))
+	d.config.emit(&Event{Type: EventDownloaded, Changes: len(createFiles) + len(removeFiles), Bytes: downloadedBytes})
 	return nil
 }
 
@@ -391,7 +450,7 @@ func (d *downstream) downloadFiles(files []*fileInformation) (string, error) {
 
 							sleep 0.1;
 					done;
-					tar -czf "$tmpFileOutput" -T "$tmpFileInput" 2>/tmp/devspace-downstream-error;
+					` + getArchiveCommand(d.config.transferCompression(), "$tmpFileOutput", "$tmpFileInput") + ` 2>/tmp/devspace-downstream-error;
 					(>&2 echo "` + StartAck + `");
 					(>&2 echo $(stat -c "%s" "$tmpFileOutput"));
 					(>&2 echo "` + EndAck + `");
@@ -71,7 +71,14 @@ func shouldUpload(relativePath string, stat os.FileInfo, s *SyncConfig, isInitia
 			return false
 		}
 
-		if isInitial {
+		// Compare the content in checksum mode
+		if unchanged, ok := s.compareChecksum(relativePath); ok {
+			if unchanged || isInitial == false || s.UploadOnly || s.DownloadOnly {
+				return unchanged == false
+			}
+		}
+
+		if isInitial && s.UploadOnly == false && s.DownloadOnly == false {
 			// File is older locally than remote so don't update remote
 			if roundMtime(stat.ModTime()) <= s.fileIndex.fileMap[relativePath].Mtime {
 				return false
@@ -112,6 +119,11 @@ func shouldDownload(fileInformation *fileInformation, s *SyncConfig) bool {
 	if s.fileIndex.fileMap[fileInformation.Name] != nil {
 		// Don't override folders that exist in the filemap
 		if fileInformation.IsDirectory == false {
+			// Redownload file if the content changed in checksum mode
+			if s.Checksum && fileInformation.Checksum != "" && s.fileIndex.fileMap[fileInformation.Name].Checksum != "" {
+				return fileInformation.Checksum != s.fileIndex.fileMap[fileInformation.Name].Checksum
+			}
+
 			// Redownload file if mtime is newer than saved one
 			if fileInformation.Mtime > s.fileIndex.fileMap[fileInformation.Name].Mtime {
 				return true
@@ -175,11 +187,20 @@ func shouldRemoveLocal(absFilepath string, fileInformation *fileInformation, s *
 			// We don't delete the file if it has changed in the map since we collected changes
 			if fileInformation.Mtime == s.fileIndex.fileMap[fileInformation.Name].Mtime && fileInformation.Size == s.fileIndex.fileMap[fileInformation.Name].Size {
 				// We don't delete the file if it has changed on the filesystem meanwhile
-				if roundMtime(stat.ModTime()) <= fileInformation.Mtime {
+				changedLocally := roundMtime(stat.ModTime()) > fileInformation.Mtime
+				if unchanged, ok := s.compareChecksum(fileInformation.Name); ok {
+					changedLocally = unchanged == false
+				}
+				if changedLocally == false {
+					return true
+				}
+
+				// The file was removed in the container, but changed locally
+				if s.resolveRemoveConflict(fileInformation.Name, true) {
 					return true
 				}
 
-				s.Logf("Skip %s because stat.ModTime() %d is greater than fileInformation.Mtime %d", absFilepath, roundMtime(stat.ModTime()), fileInformation.Mtime)
+				s.queueUpload(fileInformation.Name, stat)
 			} else {
 				s.Logf("Skip %s because Mtime (%d and %d) or Size (%d and %d) is unequal between fileInformation and fileMap", absFilepath, fileInformation.Mtime, s.fileIndex.fileMap[fileInformation.Name].Mtime, fileInformation.Size, s.fileIndex.fileMap[fileInformation.Name].Size)
 			}
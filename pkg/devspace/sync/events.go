@@ -0,0 +1,103 @@
+package sync
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/devspace-cloud/devspace/pkg/util/log"
+)
+
+// EventType describes what happened in a sync event
+type EventType string
+
+const (
+	// EventInitialSyncStarted is emitted when the initial sync starts
+	EventInitialSyncStarted EventType = "initialSyncStarted"
+
+	// EventInitialSyncDone is emitted when the initial sync is completed
+	EventInitialSyncDone EventType = "initialSyncDone"
+
+	// EventUploaded is emitted after upstream applied a batch of changes in the container
+	EventUploaded EventType = "uploaded"
+
+	// EventDownloaded is emitted after downstream applied a batch of changes locally
+	EventDownloaded EventType = "downloaded"
+
+	// EventError is emitted if an error occurred
+	EventError EventType = "error"
+
+	// EventStopped is emitted when the sync is stopped
+	EventStopped EventType = "stopped"
+)
+
+// EventLogName is the name of the file in the devspace log folder the sync events are written to
+const EventLogName = "sync-events.log"
+
+// Event is a typed sync event. Events are written as json lines to the sync event log
+type Event struct {
+	Type EventType `json:"type"`
+	Time time.Time `json:"time"`
+
+	Pod       string `json:"pod,omitempty"`
+	Local     string `json:"local"`
+	Container string `json:"container"`
+
+	// Changes and Bytes are set for uploaded and downloaded events
+	Changes int   `json:"changes,omitempty"`
+	Bytes   int64 `json:"bytes,omitempty"`
+
+	// Error is set for error events
+	Error string `json:"error,omitempty"`
+}
+
+var eventLog io.Writer
+var eventLogMutex sync.Mutex
+
+// getEventLog opens the sync event log. The event log of a previous session is truncated the first time
+func getEventLog() (io.Writer, error) {
+	eventLogMutex.Lock()
+	defer eventLogMutex.Unlock()
+
+	if eventLog == nil {
+		err := os.MkdirAll(log.Logdir, os.ModePerm)
+		if err != nil {
+			return nil, err
+		}
+
+		f, err := os.OpenFile(filepath.Join(log.Logdir, EventLogName), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, err
+		}
+
+		eventLog = f
+	}
+
+	return eventLog, nil
+}
+
+// emit completes the given event with the sync context, writes it to the event log and passes it to the OnEvent handler
+func (s *SyncConfig) emit(event *Event) {
+	event.Time = time.Now()
+	event.Local = s.WatchPath
+	event.Container = s.DestPath
+	if s.Pod != nil {
+		event.Pod = s.Pod.Name
+	}
+
+	if s.EventLog != nil {
+		out, err := json.Marshal(event)
+		if err == nil {
+			eventLogMutex.Lock()
+			s.EventLog.Write(append(out, '\n'))
+			eventLogMutex.Unlock()
+		}
+	}
+
+	if s.OnEvent != nil {
+		s.OnEvent(event)
+	}
+}
@@ -0,0 +1,47 @@
+package sync
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	k8sv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEmit(t *testing.T) {
+	eventLog := &bytes.Buffer{}
+	handled := []*Event{}
+
+	s := &SyncConfig{
+		Pod:       &k8sv1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-pod"}},
+		WatchPath: "/local",
+		DestPath:  "/app",
+		EventLog:  eventLog,
+		OnEvent: func(event *Event) {
+			handled = append(handled, event)
+		},
+	}
+
+	s.emit(&Event{Type: EventUploaded, Changes: 3, Bytes: 1024})
+	s.emit(&Event{Type: EventStopped})
+
+	if len(handled) != 2 {
+		t.Fatalf("Expected 2 handled events, got %d", len(handled))
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(eventLog.Bytes()), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 event lines, got %d: %s", len(lines), eventLog.String())
+	}
+
+	event := &Event{}
+	err := json.Unmarshal(lines[0], event)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if event.Type != EventUploaded || event.Changes != 3 || event.Bytes != 1024 || event.Pod != "test-pod" || event.Local != "/local" || event.Container != "/app" || event.Time.IsZero() {
+		t.Fatalf("Unexpected event %#v", event)
+	}
+}
@@ -8,11 +8,16 @@ import (
 type fileIndex struct {
 	fileMap      map[string]*fileInformation
 	fileMapMutex sync.Mutex
+
+	// conflicts holds the paths of conflicting files that were already reported by upstream
+	// and should be overridden by the container version during the next download
+	conflicts map[string]bool
 }
 
 func newFileIndex() *fileIndex {
 	return &fileIndex{
-		fileMap: make(map[string]*fileInformation),
+		fileMap:   make(map[string]*fileInformation),
+		conflicts: make(map[string]bool),
 	}
 }
 
@@ -28,6 +28,8 @@ type fileInformation struct {
 	RemoteMode int64 // %a
 	RemoteUID  int   // %g
 	RemoteGID  int   // %u
+
+	Checksum string // md5 checksum of the file content (only in checksum mode)
 }
 
 func (f *fileInformation) Sys() interface{} {
@@ -46,6 +48,17 @@ func (f *fileInformation) Event() notify.Event {
 	return notify.Create
 }
 
+// cloneFileInformation copies the fields that are needed to detect changes
+func cloneFileInformation(f *fileInformation) *fileInformation {
+	return &fileInformation{
+		Name:        f.Name,
+		Size:        f.Size,
+		Mtime:       f.Mtime,
+		IsDirectory: f.IsDirectory,
+		Checksum:    f.Checksum,
+	}
+}
+
 type parsingError struct {
 	msg string
 }
@@ -71,7 +84,12 @@ func parseFileInformation(fileline, destPath string) (*fileInformation, error) {
 
 	fileinfo.Name = t[0][len(destPath):]
 
+	// The sync agent appends the checksum of regular files in checksum mode
 	t = strings.Split(t[1], ",")
+	if len(t) == 7 {
+		fileinfo.Checksum = t[6]
+		t = t[:6]
+	}
 	if len(t) != 6 {
 		return nil, errors.New("[Downstream] Wrong fileline: " + fileline)
 	}
@@ -0,0 +1,100 @@
+package sync
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+
+	"github.com/bmatcuk/doublestar"
+	"github.com/devspace-cloud/devspace/pkg/devspace/kubectl"
+	"github.com/juju/errors"
+)
+
+// OnUploadCommand is a command that is executed in the container after files were uploaded
+type OnUploadCommand struct {
+	// Paths are glob patterns relative to the sync path (e.g. package.json or src/**/*.go). If empty, every upload triggers the command
+	Paths []string
+
+	// Command is the command and its arguments that are executed in the container
+	Command []string
+}
+
+func validateOnUploadCommands(commands []*OnUploadCommand) error {
+	for _, command := range commands {
+		if len(command.Command) == 0 {
+			return errors.New("onUpload command is empty")
+		}
+
+		for _, pattern := range command.Paths {
+			_, err := doublestar.Match(normalizeOnUploadPath(pattern), normalizeOnUploadPath(pattern))
+			if err != nil {
+				return errors.Errorf("Invalid onUpload path %s: %v", pattern, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// matches returns true if one of the uploaded files matches the paths of the command
+func (c *OnUploadCommand) matches(uploadedFiles map[string]*fileInformation) bool {
+	if len(c.Paths) == 0 {
+		return len(uploadedFiles) > 0
+	}
+
+	for name := range uploadedFiles {
+		for _, pattern := range c.Paths {
+			matched, _ := doublestar.Match(normalizeOnUploadPath(pattern), normalizeOnUploadPath(name))
+			if matched {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func normalizeOnUploadPath(path string) string {
+	return strings.TrimPrefix(path, "/")
+}
+
+// runOnUploadCommands executes all commands whose paths match one of the uploaded files. Failing commands
+// are reported in the sync log, but do not stop the sync
+func (u *upstream) runOnUploadCommands(uploadedFiles map[string]*fileInformation) {
+	for _, command := range u.config.OnUpload {
+		if command.matches(uploadedFiles) == false {
+			continue
+		}
+
+		u.config.Logf("[OnUpload] Execute '%s'", strings.Join(command.Command, " "))
+
+		stdout, stderr, err := u.execOnUploadCommand(command.Command)
+		for _, line := range strings.Split(strings.TrimSpace(string(stdout)+string(stderr)), "\n") {
+			if line != "" {
+				u.config.Logf("[OnUpload] %s", line)
+			}
+		}
+
+		if err != nil {
+			u.config.Logf("[OnUpload] Command '%s' failed: %v", strings.Join(command.Command, " "), err)
+		}
+	}
+}
+
+func (u *upstream) execOnUploadCommand(command []string) ([]byte, []byte, error) {
+	if u.config.testing == false {
+		return kubectl.ExecBuffered(u.config.DevSpaceConfig, u.config.Kubectl, u.config.Pod, u.config.Container.Name, command)
+	}
+
+	// In tests we execute the command locally in the destination path
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Dir = u.config.DestPath
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err := cmd.Run()
+	return stdout.Bytes(), stderr.Bytes(), err
+}
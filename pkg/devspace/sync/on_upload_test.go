@@ -0,0 +1,36 @@
+package sync
+
+import "testing"
+
+func TestOnUploadCommandMatches(t *testing.T) {
+	testCases := []struct {
+		paths    []string
+		uploaded []string
+		expected bool
+	}{
+		{paths: nil, uploaded: []string{"/main.go"}, expected: true},
+		{paths: nil, uploaded: []string{}, expected: false},
+		{paths: []string{"package.json"}, uploaded: []string{"/src/index.js", "/package.json"}, expected: true},
+		{paths: []string{"package.json"}, uploaded: []string{"/src/package.json"}, expected: false},
+		{paths: []string{"/src/*.js"}, uploaded: []string{"/src/index.js"}, expected: true},
+		{paths: []string{"**/*.go"}, uploaded: []string{"/pkg/util/util.go"}, expected: true},
+		{paths: []string{"**/*.go"}, uploaded: []string{"/README.md"}, expected: false},
+	}
+
+	for _, testCase := range testCases {
+		uploaded := make(map[string]*fileInformation)
+		for _, name := range testCase.uploaded {
+			uploaded[name] = &fileInformation{Name: name}
+		}
+
+		command := &OnUploadCommand{Paths: testCase.paths, Command: []string{"true"}}
+		if command.matches(uploaded) != testCase.expected {
+			t.Fatalf("Paths %v with uploaded files %v: expected %v", testCase.paths, testCase.uploaded, testCase.expected)
+		}
+	}
+
+	err := validateOnUploadCommands([]*OnUploadCommand{{Paths: []string{"[a-"}, Command: []string{"true"}}})
+	if err == nil {
+		t.Fatal("Expected error for invalid pattern")
+	}
+}
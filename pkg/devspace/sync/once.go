@@ -0,0 +1,292 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/juju/errors"
+)
+
+// ChangeType describes what a one-shot sync does with a file
+type ChangeType string
+
+const (
+	// ChangeCreate means the file does not exist on the target side and is created
+	ChangeCreate ChangeType = "create"
+
+	// ChangeUpdate means the file differs on the target side and is overwritten
+	ChangeUpdate ChangeType = "update"
+
+	// ChangeDelete means the file only exists on the target side and is deleted
+	ChangeDelete ChangeType = "delete"
+)
+
+// Change is a single file change of a one-shot sync
+type Change struct {
+	Type      ChangeType
+	Path      string
+	Directory bool
+
+	file *fileInformation
+}
+
+// Plan holds the changes a one-shot sync applies within the container (Upload) and locally (Download)
+type Plan struct {
+	Upload   []*Change
+	Download []*Change
+}
+
+// Empty returns true if the local path and the container path are in sync
+func (p *Plan) Empty() bool {
+	return len(p.Upload) == 0 && len(p.Download) == 0
+}
+
+// Diff compares the local path with the container path and returns the changes a one-shot sync would apply
+// without changing any files
+func (s *SyncConfig) Diff() (*Plan, error) {
+	err := s.connect()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	defer s.Stop(nil)
+
+	return s.plan()
+}
+
+// RunOnce synchronizes the local path and the container path a single time and returns the applied changes
+func (s *SyncConfig) RunOnce() (*Plan, error) {
+	err := s.connect()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	defer s.Stop(nil)
+
+	plan, err := s.plan()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	err = s.applyPlan(plan)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return plan, nil
+}
+
+// connect starts the container shells without watching for changes
+func (s *SyncConfig) connect() error {
+	err := s.setup()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	err = s.upstream.start()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	err = s.negotiateCompression(s.upstream.stdinPipe, s.upstream.stdoutPipe)
+	if err != nil {
+		s.Stop(nil)
+		return errors.Trace(err)
+	}
+
+	s.downstream.interrupt = make(chan bool, 1)
+
+	err = s.downstream.startShell()
+	if err != nil {
+		s.Stop(nil)
+		return errors.Trace(err)
+	}
+
+	return nil
+}
+
+// diffInitial compares the local path with the container path. It returns the local files that should be
+// uploaded and the container files that don't exist locally
+func (s *SyncConfig) diffInitial() ([]*fileInformation, []*fileInformation, error) {
+	err := s.downstream.populateFileMap()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	localChanges := make([]*fileInformation, 0, 10)
+	fileMapClone := make(map[string]*fileInformation)
+
+	s.fileIndex.fileMapMutex.Lock()
+	for key, element := range s.fileIndex.fileMap {
+		if element.IsSymbolicLink {
+			continue
+		}
+
+		fileMapClone[key] = element
+	}
+	s.fileIndex.fileMapMutex.Unlock()
+
+	err = s.diffServerClient(s.WatchPath, &localChanges, fileMapClone, false)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	remoteChanges := make([]*fileInformation, 0, len(fileMapClone))
+	for _, element := range fileMapClone {
+		remoteChanges = append(remoteChanges, element)
+	}
+
+	return localChanges, remoteChanges, nil
+}
+
+// splitLocalChanges splits the local changes into files that exist in the container and files that only exist locally
+func (s *SyncConfig) splitLocalChanges(localChanges []*fileInformation) ([]*fileInformation, []*fileInformation) {
+	s.fileIndex.fileMapMutex.Lock()
+	defer s.fileIndex.fileMapMutex.Unlock()
+
+	remoteVersions := make([]*fileInformation, 0, len(localChanges))
+	localOnly := make([]*fileInformation, 0, len(localChanges))
+	for _, change := range localChanges {
+		remote := s.fileIndex.fileMap[change.Name]
+		if remote == nil {
+			localOnly = append(localOnly, change)
+		} else if remote.IsDirectory == false {
+			remoteVersions = append(remoteVersions, remote)
+		}
+	}
+
+	return remoteVersions, localOnly
+}
+
+func (s *SyncConfig) plan() (*Plan, error) {
+	localChanges, remoteChanges, err := s.diffInitial()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	plan := &Plan{
+		Upload:   []*Change{},
+		Download: []*Change{},
+	}
+
+	if s.DownloadOnly {
+		remoteVersions, localOnly := s.splitLocalChanges(localChanges)
+		for _, file := range remoteVersions {
+			plan.Download = append(plan.Download, &Change{Type: ChangeUpdate, Path: file.Name, file: file})
+		}
+		if s.Delete {
+			for _, file := range localOnly {
+				plan.Download = append(plan.Download, &Change{Type: ChangeDelete, Path: file.Name, Directory: file.IsDirectory, file: file})
+			}
+		}
+	} else {
+		s.fileIndex.fileMapMutex.Lock()
+		uploads := make(map[string]bool, len(localChanges))
+		for _, file := range localChanges {
+			changeType := ChangeCreate
+			if s.fileIndex.fileMap[file.Name] != nil {
+				changeType = ChangeUpdate
+			}
+
+			uploads[file.Name] = true
+			plan.Upload = append(plan.Upload, &Change{Type: changeType, Path: file.Name, Directory: file.IsDirectory, file: file})
+		}
+
+		if s.UploadOnly == false {
+			for _, file := range s.remoteNewerFiles(uploads) {
+				plan.Download = append(plan.Download, &Change{Type: ChangeUpdate, Path: file.Name, file: file})
+			}
+		}
+		s.fileIndex.fileMapMutex.Unlock()
+	}
+
+	for _, file := range remoteChanges {
+		if s.UploadOnly == false {
+			plan.Download = append(plan.Download, &Change{Type: ChangeCreate, Path: file.Name, Directory: file.IsDirectory, file: file})
+		} else if s.Delete {
+			plan.Upload = append(plan.Upload, &Change{Type: ChangeDelete, Path: file.Name, Directory: file.IsDirectory, file: &fileInformation{Name: file.Name}})
+		}
+	}
+
+	sortChanges(plan.Upload)
+	sortChanges(plan.Download)
+	return plan, nil
+}
+
+// remoteNewerFiles returns the container files that exist locally as well, but were changed more recently
+// in the container. s.fileIndex needs to be locked before this function is called
+func (s *SyncConfig) remoteNewerFiles(uploads map[string]bool) []*fileInformation {
+	files := []*fileInformation{}
+	for name, remote := range s.fileIndex.fileMap {
+		if uploads[name] || remote.IsDirectory || remote.IsSymbolicLink {
+			continue
+		}
+		if s.ignoreMatcher != nil && s.ignoreMatcher.MatchesPath(name) {
+			continue
+		}
+		if s.downloadIgnoreMatcher != nil && s.downloadIgnoreMatcher.MatchesPath(name) {
+			continue
+		}
+
+		stat, err := os.Stat(filepath.Join(s.WatchPath, name))
+		if err != nil || stat.IsDir() {
+			continue
+		}
+
+		if remote.Mtime > roundMtime(stat.ModTime()) {
+			files = append(files, remote)
+		}
+	}
+
+	return files
+}
+
+func sortChanges(changes []*Change) {
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+}
+
+func (s *SyncConfig) applyPlan(plan *Plan) error {
+	uploads := []*fileInformation{}
+	for _, change := range plan.Upload {
+		uploads = append(uploads, change.file)
+	}
+
+	for i := 0; i < len(uploads); i += initialUpstreamBatchSize {
+		end := i + initialUpstreamBatchSize
+		if end > len(uploads) {
+			end = len(uploads)
+		}
+
+		err := s.upstream.applyChanges(uploads[i:end])
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	downloads := []*fileInformation{}
+	for _, change := range plan.Download {
+		if change.Type != ChangeDelete {
+			downloads = append(downloads, change.file)
+			continue
+		}
+
+		err := os.RemoveAll(filepath.Join(s.WatchPath, change.Path))
+		if err != nil {
+			return errors.Trace(err)
+		}
+
+		s.Logf("[Downstream] Remove %s", change.Path)
+	}
+
+	if len(downloads) > 0 {
+		err := s.downstream.applyChanges(downloads, nil)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package sync
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func createOnceTestFiles(t *testing.T, local, remote string) {
+	oldTime := time.Now().Add(-time.Hour)
+
+	files := map[string]string{
+		filepath.Join(local, "local"):   "local",
+		filepath.Join(remote, "remote"): "remote",
+		filepath.Join(local, "both"):    "new content",
+		filepath.Join(remote, "both"):   "old",
+		filepath.Join(local, "stale"):   "old",
+		filepath.Join(remote, "stale"):  "new content",
+	}
+	for name, content := range files {
+		err := ioutil.WriteFile(name, []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, name := range []string{filepath.Join(remote, "both"), filepath.Join(local, "stale")} {
+		err := os.Chtimes(name, oldTime, oldTime)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Skipping test on non linux platform")
+	}
+
+	remote, local, outside := initTestDirs(t)
+	defer os.RemoveAll(remote)
+	defer os.RemoveAll(local)
+	defer os.RemoveAll(outside)
+
+	createOnceTestFiles(t, local, remote)
+
+	plan, err := createTestSyncClient(local, remote).Diff()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(plan.Upload) != 2 || plan.Upload[0].Path != "/both" || plan.Upload[0].Type != ChangeUpdate || plan.Upload[1].Path != "/local" || plan.Upload[1].Type != ChangeCreate {
+		t.Fatalf("Unexpected upload changes %#v", plan.Upload)
+	}
+	if len(plan.Download) != 2 || plan.Download[0].Path != "/remote" || plan.Download[0].Type != ChangeCreate || plan.Download[1].Path != "/stale" || plan.Download[1].Type != ChangeUpdate {
+		t.Fatalf("Unexpected download changes %#v", plan.Download)
+	}
+
+	// Diff must not change any files
+	_, err = os.Stat(filepath.Join(local, "remote"))
+	if os.IsNotExist(err) == false {
+		t.Fatalf("Expected %s to not exist", filepath.Join(local, "remote"))
+	}
+}
+
+func TestRunOnceUploadOnly(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Skipping test on non linux platform")
+	}
+
+	remote, local, outside := initTestDirs(t)
+	defer os.RemoveAll(remote)
+	defer os.RemoveAll(local)
+	defer os.RemoveAll(outside)
+
+	createOnceTestFiles(t, local, remote)
+
+	syncClient := createTestSyncClient(local, remote)
+	syncClient.UploadOnly = true
+	syncClient.Delete = true
+
+	plan, err := syncClient.RunOnce()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(plan.Upload) != 4 || len(plan.Download) != 0 {
+		t.Fatalf("Unexpected changes %#v %#v", plan.Upload, plan.Download)
+	}
+
+	for name, expected := range map[string]string{"local": "local", "both": "new content"} {
+		content, err := ioutil.ReadFile(filepath.Join(remote, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != expected {
+			t.Fatalf("Expected %s in the container to be %q, got %q", name, expected, string(content))
+		}
+	}
+
+	_, err = os.Stat(filepath.Join(remote, "remote"))
+	if os.IsNotExist(err) == false {
+		t.Fatalf("Expected %s to be deleted", filepath.Join(remote, "remote"))
+	}
+}
+
+func TestRunOnceDownloadsNewerRemoteFiles(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Skipping test on non linux platform")
+	}
+
+	remote, local, outside := initTestDirs(t)
+	defer os.RemoveAll(remote)
+	defer os.RemoveAll(local)
+	defer os.RemoveAll(outside)
+
+	createOnceTestFiles(t, local, remote)
+
+	_, err := createTestSyncClient(local, remote).RunOnce()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, expected := range map[string]string{filepath.Join(local, "stale"): "new content", filepath.Join(remote, "both"): "new content"} {
+		content, err := ioutil.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != expected {
+			t.Fatalf("Expected %s to be %q, got %q", name, expected, string(content))
+		}
+	}
+}
@@ -1,10 +1,12 @@
 package sync
 
 import (
+	"io"
 	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -47,6 +49,46 @@ type SyncConfig struct {
 	DownstreamLimit      int64
 	Verbose              bool
 
+	// UseAgent injects the sync agent into the container to watch for remote changes instead of polling
+	UseAgent bool
+
+	// UploadOnly only uploads local changes. Changes within the container are never downloaded and
+	// local changes always overwrite the container version
+	UploadOnly bool
+
+	// DownloadOnly only downloads changes within the container. Local changes are never uploaded and
+	// the container version always overwrites local files
+	DownloadOnly bool
+
+	// Delete removes files that only exist on the target side during a one-shot upload only or download only sync
+	Delete bool
+
+	// Checksum compares the md5 checksums of files instead of mtime and size to detect changes
+	Checksum bool
+
+	// FileMode and DirMode override the permissions of uploaded files and folders within the container (Default: 0 = local permissions)
+	FileMode int64
+	DirMode  int64
+
+	// UID and GID override the owner of uploaded files and folders within the container. The container has to run as root to change the owner
+	UID *int
+	GID *int
+
+	// Compression is the compression of the transferred archives: auto, zstd, gzip or none (Default: auto)
+	Compression string
+
+	// ConflictPolicy decides which version is kept if a file changed locally and in the container (Default: newest-wins)
+	ConflictPolicy string
+
+	// EventLog receives every sync event as json line (Default: .devspace/logs/sync-events.log)
+	EventLog io.Writer
+
+	// OnEvent is called for every sync event
+	OnEvent func(event *Event)
+
+	// OnUpload holds commands that are executed in the container after matching files were uploaded
+	OnUpload []*OnUploadCommand
+
 	// These channels can be used to listen for certain sync events
 	DownstreamInitialSyncDone chan bool
 	UpstreamInitialSyncDone   chan bool
@@ -65,6 +107,9 @@ type SyncConfig struct {
 	upstream   *upstream
 	downstream *downstream
 
+	// compression is the compression negotiated with the container
+	compression string
+
 	silent   bool
 	stopOnce sync.Once
 
@@ -108,6 +153,8 @@ func (s *SyncConfig) Error(err error) {
 		s.CustomLog.WithKey("local", s.WatchPath).WithKey("container", s.DestPath).Errorf("Error: %v, Stack: %v", err, errors.ErrorStack(err))
 	}
 
+	s.emit(&Event{Type: EventError, Error: err.Error()})
+
 	if s.errorChan != nil {
 		s.errorChan <- err
 	}
@@ -122,6 +169,37 @@ func (s *SyncConfig) setup() error {
 
 	s.WatchPath = realLocalPath
 
+	if s.ConflictPolicy != "" && isValidConflictPolicy(s.ConflictPolicy) == false {
+		return errors.Errorf("Unknown conflict policy %s, supported policies are: %s", s.ConflictPolicy, strings.Join(ConflictPolicies, ", "))
+	}
+
+	// Upload only always keeps the local version and download only the container version
+	if s.UploadOnly && s.DownloadOnly {
+		return errors.New("Upload only and download only can't be used together")
+	} else if s.UploadOnly {
+		if s.ConflictPolicy != "" && s.ConflictPolicy != ConflictPolicyLocalWins {
+			return errors.Errorf("Conflict policy %s can't be used with upload only", s.ConflictPolicy)
+		}
+
+		s.ConflictPolicy = ConflictPolicyLocalWins
+	} else if s.DownloadOnly {
+		if s.ConflictPolicy != "" && s.ConflictPolicy != ConflictPolicyRemoteWins && s.ConflictPolicy != ConflictPolicyKeepBoth {
+			return errors.Errorf("Conflict policy %s can't be used with download only", s.ConflictPolicy)
+		}
+		if s.ConflictPolicy == "" {
+			s.ConflictPolicy = ConflictPolicyRemoteWins
+		}
+	}
+
+	if s.Compression != "" && isValidCompression(s.Compression) == false {
+		return errors.Errorf("Unknown compression %s, supported values are: %s", s.Compression, strings.Join(Compressions, ", "))
+	}
+
+	err = validateOnUploadCommands(s.OnUpload)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
 	if s.ExcludePaths == nil {
 		s.ExcludePaths = make([]string, 0, 2)
 	}
@@ -149,6 +227,13 @@ func (s *SyncConfig) setup() error {
 		s.CustomLog = syncLog
 	}
 
+	if s.EventLog == nil && s.testing == false && s.silent == false {
+		s.EventLog, err = getEventLog()
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+
 	err = s.initIgnoreParsers()
 	if err != nil {
 		return errors.Trace(err)
@@ -179,6 +264,12 @@ func (s *SyncConfig) Start() error {
 		return errors.Trace(err)
 	}
 
+	err = s.negotiateCompression(s.upstream.stdinPipe, s.upstream.stdoutPipe)
+	if err != nil {
+		s.Stop(nil)
+		return errors.Trace(err)
+	}
+
 	err = s.downstream.start()
 	if err != nil {
 		s.Stop(nil)
@@ -209,15 +300,14 @@ func (s *SyncConfig) initIgnoreParsers() error {
 		s.downloadIgnoreMatcher = ignoreMatcher
 	}
 
-	if s.UploadExcludePaths != nil {
-		ignoreMatcher, err := compilePaths(s.UploadExcludePaths)
-		if err != nil {
-			return errors.Trace(err)
-		}
-
-		s.uploadIgnoreMatcher = ignoreMatcher
+	// Conflict copies are never uploaded
+	ignoreMatcher, err := compilePaths(append(append([]string{}, s.UploadExcludePaths...), conflictExcludePaths...))
+	if err != nil {
+		return errors.Trace(err)
 	}
 
+	s.uploadIgnoreMatcher = ignoreMatcher
+
 	return nil
 }
 
@@ -225,11 +315,15 @@ func (s *SyncConfig) mainLoop() {
 	s.Logf("[Sync] Start syncing")
 
 	// Start upstream as early as possible
-	go s.startUpstream()
+	if s.DownloadOnly == false {
+		go s.startUpstream()
+	} else if s.readyChan != nil {
+		s.readyChan <- true
+	}
 
 	// Start downstream and do initial sync
 	go func() {
-		defer s.Stop(nil)
+		s.emit(&Event{Type: EventInitialSyncStarted})
 
 		err := s.initialSync()
 		if err != nil {
@@ -238,6 +332,13 @@ func (s *SyncConfig) mainLoop() {
 		}
 
 		s.Logf("[Sync] Initial sync completed")
+		s.emit(&Event{Type: EventInitialSyncDone})
+
+		// Upload only syncs never watch the container for changes
+		if s.UploadOnly {
+			return
+		}
+
 		s.startDownstream()
 	}()
 
@@ -275,27 +376,18 @@ func (s *SyncConfig) startDownstream() {
 }
 
 func (s *SyncConfig) initialSync() error {
-	err := s.downstream.populateFileMap()
+	localChanges, remoteChanges, err := s.diffInitial()
 	if err != nil {
 		return errors.Trace(err)
 	}
 
-	localChanges := make([]*fileInformation, 0, 10)
-	fileMapClone := make(map[string]*fileInformation)
-
-	s.fileIndex.fileMapMutex.Lock()
-	for key, element := range s.fileIndex.fileMap {
-		if element.IsSymbolicLink {
-			continue
-		}
-
-		fileMapClone[key] = element
-	}
-	s.fileIndex.fileMapMutex.Unlock()
-
-	err = s.diffServerClient(s.WatchPath, &localChanges, fileMapClone, false)
-	if err != nil {
-		return errors.Trace(err)
+	if s.UploadOnly {
+		remoteChanges = nil
+	} else if s.DownloadOnly {
+		// Local files that differ are overridden with the container version
+		remoteVersions, _ := s.splitLocalChanges(localChanges)
+		remoteChanges = append(remoteChanges, remoteVersions...)
+		localChanges = nil
 	}
 
 	// Upstream initial sync
@@ -307,12 +399,7 @@ func (s *SyncConfig) initialSync() error {
 		}
 	}()
 
-	if len(fileMapClone) > 0 {
-		remoteChanges := make([]*fileInformation, 0, len(fileMapClone))
-		for _, element := range fileMapClone {
-			remoteChanges = append(remoteChanges, element)
-		}
-
+	if len(remoteChanges) > 0 {
 		err = s.downstream.applyChanges(remoteChanges, nil)
 		if err != nil {
 			return errors.Trace(err)
@@ -444,7 +531,7 @@ func (s *SyncConfig) sendChangesToUpstream(changes []*fileInformation) {
 		s.fileIndex.fileMapMutex.Lock()
 
 		for i := j; i < (j+initialUpstreamBatchSize) && i < len(changes); i++ {
-			if s.fileIndex.fileMap[changes[i].Name] == nil || (s.fileIndex.fileMap[changes[i].Name] != nil && changes[i].Mtime > s.fileIndex.fileMap[changes[i].Name].Mtime) {
+			if s.UploadOnly || s.fileIndex.fileMap[changes[i].Name] == nil || (s.fileIndex.fileMap[changes[i].Name] != nil && changes[i].Mtime > s.fileIndex.fileMap[changes[i].Name].Mtime) {
 				sendBatch = append(sendBatch, changes[i])
 			}
 		}
@@ -485,6 +572,7 @@ func (s *SyncConfig) Stop(fatalError error) {
 
 		if s.downstream != nil && s.downstream.interrupt != nil {
 			close(s.downstream.interrupt)
+			s.downstream.stopAgent()
 
 			if s.downstream.stdinPipe != nil {
 				s.downstream.stdinPipe.Write([]byte("exit\n"))
@@ -501,6 +589,7 @@ func (s *SyncConfig) Stop(fatalError error) {
 		}
 
 		s.Logln("[Sync] Sync stopped")
+		s.emit(&Event{Type: EventStopped})
 		if s.SyncDone != nil {
 			close(s.SyncDone)
 		}
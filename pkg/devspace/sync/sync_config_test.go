@@ -50,6 +50,16 @@ func initTestDirs(t *testing.T) (string, string, string) {
 	return testRemotePath, testLocalPath, outside
 }
 
+// useTestLogdir writes file logs into the given test dir instead of the package folder
+func useTestLogdir(dir string) func() {
+	logdir := log.Logdir
+	log.Logdir = dir + "/"
+
+	return func() {
+		log.Logdir = logdir
+	}
+}
+
 func createTestSyncClient(testLocalPath, testRemotePath string) *SyncConfig {
 	syncLog = log.GetInstance()
 
@@ -120,6 +130,18 @@ func TestInitialSync(t *testing.T) {
 }
 
 func TestNormalSync(t *testing.T) {
+	testNormalSync(t, false, false)
+}
+
+func TestNormalSyncWithAgent(t *testing.T) {
+	testNormalSync(t, true, false)
+}
+
+func TestNormalSyncWithChecksum(t *testing.T) {
+	testNormalSync(t, false, true)
+}
+
+func testNormalSync(t *testing.T, useAgent bool, checksum bool) {
 	if runtime.GOOS != "linux" {
 		t.Skip("Skipping test on non linux systems")
 	}
@@ -134,6 +156,8 @@ func TestNormalSync(t *testing.T) {
 	sort.Stable(foldersToCheck)
 
 	syncClient := createTestSyncClient(local, remote)
+	syncClient.UseAgent = useAgent
+	syncClient.Checksum = checksum
 	defer syncClient.Stop(nil)
 
 	syncClient.errorChan = make(chan error)
@@ -159,6 +183,9 @@ func TestNormalSync(t *testing.T) {
 		t.Error(err)
 		return
 	}
+	if useAgent && syncClient.downstream.getAgentStdout() == nil {
+		t.Fatal("Sync agent was not started")
+	}
 
 	syncClient.readyChan = make(chan bool)
 
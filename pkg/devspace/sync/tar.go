@@ -2,7 +2,8 @@ package sync
 
 import (
 	"archive/tar"
-	"compress/gzip"
+	"crypto/md5"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -15,14 +16,14 @@ import (
 
 func untarAll(reader io.Reader, destPath, prefix string, config *SyncConfig) error {
 	fileCounter := 0
-	gzr, err := gzip.NewReader(reader)
+	decompressReader, err := newDecompressReader(reader, config.transferCompression())
 	if err != nil {
 		return fmt.Errorf("Error decompressing: %v", err)
 	}
 
-	defer gzr.Close()
+	defer decompressReader.Close()
 
-	tarReader := tar.NewReader(gzr)
+	tarReader := tar.NewReader(decompressReader)
 
 	for {
 		shouldContinue, err := untarNext(tarReader, destPath, prefix, config)
@@ -58,11 +59,46 @@ func untarNext(tarReader *tar.Reader, destPath, prefix string, config *SyncConfi
 	outFileName := path.Join(destPath, relativePath)
 	baseName := path.Dir(outFileName)
 
-	// Check if newer file is there and then don't override?
+	// Check if the local file changed as well and then don't override?
 	stat, err := os.Stat(outFileName)
 
-	if err == nil {
-		if roundMtime(stat.ModTime()) > header.FileInfo().ModTime().Unix() {
+	if err == nil && stat.IsDir() == false && header.FileInfo().IsDir() == false {
+		remoteMtime := header.FileInfo().ModTime().Unix()
+		uploadExcluded := config.uploadIgnoreMatcher != nil && config.uploadIgnoreMatcher.MatchesPath(relativePath)
+
+		if config.fileIndex.conflicts[relativePath] {
+			// Upstream already reported the conflict and decided for the container version
+			delete(config.fileIndex.conflicts, relativePath)
+
+			if config.keepConflictCopy(relativePath, outFileName, stat) == false {
+				return true, nil
+			}
+		} else if uploadExcluded == false && hasChangedLocally(relativePath, stat, config) {
+			if config.resolveConflict(relativePath, roundMtime(stat.ModTime()), remoteMtime) {
+				// Remember the container version as synced, so that upstream uploads the local version
+				config.fileIndex.fileMap[relativePath] = &fileInformation{
+					Name:  relativePath,
+					Mtime: remoteMtime,
+					Size:  header.Size,
+				}
+
+				if config.Checksum {
+					hash := md5.New()
+					if _, err := io.Copy(hash, tarReader); err != nil {
+						return false, errors.Trace(err)
+					}
+
+					config.fileIndex.fileMap[relativePath].Checksum = hex.EncodeToString(hash.Sum(nil))
+				}
+
+				config.queueUpload(relativePath, stat)
+				return true, nil
+			}
+
+			if config.keepConflictCopy(relativePath, outFileName, stat) == false {
+				return true, nil
+			}
+		} else if config.Checksum == false && roundMtime(stat.ModTime()) > remoteMtime {
 			// Update filemap otherwise we download and download again
 			config.fileIndex.fileMap[relativePath] = &fileInformation{
 				Name:        relativePath,
@@ -108,7 +144,13 @@ func untarNext(tarReader *tar.Reader, destPath, prefix string, config *SyncConfi
 
 	defer outFile.Close()
 
-	if _, err := io.Copy(outFile, tarReader); err != nil {
+	var writer io.Writer = outFile
+	hash := md5.New()
+	if config.Checksum {
+		writer = io.MultiWriter(outFile, hash)
+	}
+
+	if _, err := io.Copy(writer, tarReader); err != nil {
 		return false, errors.Trace(err)
 	}
 
@@ -123,6 +165,9 @@ func untarNext(tarReader *tar.Reader, destPath, prefix string, config *SyncConfi
 		// Set owner & group correctly
 		// TODO: Enable this on supported platforms
 		// _ = os.Chown(outFileName, stat.Sys().(*syscall.Stat).Uid, stat.Sys().(*syscall.Stat_t).Gid)
+	} else {
+		// New files get the container permissions, but are always readable and writable by the local user
+		_ = os.Chmod(outFileName, getLocalFileMode(header.Mode))
 	}
 
 	// Set mod time correctly
@@ -140,6 +185,10 @@ func untarNext(tarReader *tar.Reader, destPath, prefix string, config *SyncConfi
 		IsDirectory: false,
 	}
 
+	if config.Checksum {
+		config.fileIndex.fileMap[relativePath].Checksum = hex.EncodeToString(hash.Sum(nil))
+	}
+
 	return true, nil
 }
 
@@ -153,19 +202,29 @@ func writeTar(files []*fileInformation, config *SyncConfig) (string, map[string]
 	defer f.Close()
 
 	// Use compression
-	gw := gzip.NewWriter(f)
-	defer gw.Close()
+	compressWriter, err := newCompressWriter(f, config.transferCompression())
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+
+	defer compressWriter.Close()
 
-	tarWriter := tar.NewWriter(gw)
+	tarWriter := tar.NewWriter(compressWriter)
 	defer tarWriter.Close()
 
 	writtenFiles := make(map[string]*fileInformation)
+	writtenFolders := make(map[string]bool)
 
 	for _, element := range files {
 		relativePath := element.Name
 
 		if writtenFiles[relativePath] == nil {
-			err := recursiveTar(config.WatchPath, relativePath, writtenFiles, tarWriter, config)
+			err := writeParentFolders(config.WatchPath, relativePath, writtenFolders, tarWriter, config)
+			if err != nil {
+				return "", nil, errors.Trace(err)
+			}
+
+			err = recursiveTar(config.WatchPath, relativePath, writtenFiles, tarWriter, config)
 
 			if err != nil {
 				config.Logf("[Upstream] Tar failed: %s. Will retry in 4 seconds...\n", err.Error())
@@ -250,6 +309,8 @@ func tarFolder(basePath string, fileInformation *fileInformation, writtenFiles m
 		}
 		config.fileIndex.fileMapMutex.Unlock()
 
+		setRemotePermissions(hdr, config)
+
 		if err := tw.WriteHeader(hdr); err != nil {
 			return errors.Trace(err)
 		}
@@ -291,18 +352,95 @@ func tarFile(basePath string, fileInformation *fileInformation, writtenFiles map
 	}
 	config.fileIndex.fileMapMutex.Unlock()
 
+	setRemotePermissions(hdr, config)
+
 	if err := tw.WriteHeader(hdr); err != nil {
 		return errors.Trace(err)
 	}
 
-	if _, err := io.Copy(tw, f); err != nil {
+	var writer io.Writer = tw
+	hash := md5.New()
+	if config.Checksum {
+		writer = io.MultiWriter(tw, hash)
+	}
+
+	if _, err := io.Copy(writer, f); err != nil {
 		return errors.Trace(err)
 	}
 
+	if config.Checksum {
+		fileInformation.Checksum = hex.EncodeToString(hash.Sum(nil))
+	}
+
 	writtenFiles[fileInformation.Name] = fileInformation
 	return f.Close()
 }
 
+// setRemotePermissions overrides the mode and owner of the tar header with the configured container permissions
+func setRemotePermissions(hdr *tar.Header, config *SyncConfig) {
+	if hdr.Typeflag == tar.TypeDir && config.DirMode != 0 {
+		hdr.Mode = config.DirMode
+	} else if hdr.Typeflag != tar.TypeDir && config.FileMode != 0 {
+		hdr.Mode = config.FileMode
+	}
+
+	// Clear the user and group names, otherwise tar would prefer the local names over the ids
+	if config.UID != nil {
+		hdr.Uid = *config.UID
+		hdr.Uname = ""
+	}
+	if config.GID != nil {
+		hdr.Gid = *config.GID
+		hdr.Gname = ""
+	}
+}
+
+// getLocalFileMode returns the local permissions of a downloaded file that did not exist locally before
+func getLocalFileMode(remoteMode int64) os.FileMode {
+	return os.FileMode(remoteMode&0777) | 0600
+}
+
+// writeParentFolders adds the parent folders of the given path to the archive, so that the configured folder
+// permissions and owner are applied to them as well. Otherwise tar creates them with the permissions of the container user
+func writeParentFolders(basePath, relativePath string, writtenFolders map[string]bool, tw *tar.Writer, config *SyncConfig) error {
+	if config.DirMode == 0 && config.UID == nil && config.GID == nil {
+		return nil
+	}
+
+	parents := []string{}
+	for parent := path.Dir(relativePath); parent != "/" && parent != "." && parent != ""; parent = path.Dir(parent) {
+		parents = append([]string{parent}, parents...)
+	}
+
+	for _, parent := range parents {
+		if writtenFolders[parent] {
+			continue
+		}
+
+		stat, err := os.Stat(path.Join(basePath, parent))
+		if err != nil {
+			return errors.Trace(err)
+		}
+
+		hdr, err := tar.FileInfoHeader(stat, "")
+		if err != nil {
+			return errors.Trace(err)
+		}
+
+		hdr.Name = parent
+		setRemotePermissions(hdr, config)
+
+		err = tw.WriteHeader(hdr)
+		if err != nil {
+			return errors.Trace(err)
+		}
+
+		writtenFolders[parent] = true
+	}
+
+	return nil
+}
+
 func createFileInformationFromStat(relativePath string, stat os.FileInfo, config *SyncConfig) *fileInformation {
 	config.fileIndex.fileMapMutex.Lock()
 	defer config.fileIndex.fileMapMutex.Unlock()
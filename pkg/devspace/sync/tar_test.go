@@ -0,0 +1,88 @@
+package sync
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteTarPermissions(t *testing.T) {
+	local, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(local)
+
+	err = os.MkdirAll(filepath.Join(local, "folder"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ioutil.WriteFile(filepath.Join(local, "folder", "file"), []byte("content"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uid, gid := 1000, 2000
+	s := &SyncConfig{
+		WatchPath: local,
+		FileMode:  0660,
+		DirMode:   0770,
+		UID:       &uid,
+		GID:       &gid,
+		fileIndex: newFileIndex(),
+		silent:    true,
+	}
+
+	filename, writtenFiles, err := writeTar([]*fileInformation{{Name: "/folder/file", Size: 7}}, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(filename)
+
+	if len(writtenFiles) != 1 {
+		t.Fatalf("Expected 1 written file, got %d", len(writtenFiles))
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+
+	gzr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	headers := map[string]*tar.Header{}
+	tarReader := tar.NewReader(gzr)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+
+		headers[header.Name] = header
+	}
+
+	if headers["/folder"] == nil || headers["/folder"].Mode != 0770 || headers["/folder"].Uid != uid || headers["/folder"].Gid != gid {
+		t.Fatalf("Unexpected folder header %#v", headers["/folder"])
+	}
+	if headers["/folder/file"] == nil || headers["/folder/file"].Mode != 0660 || headers["/folder/file"].Uid != uid || headers["/folder/file"].Gid != gid || headers["/folder/file"].Uname != "" {
+		t.Fatalf("Unexpected file header %#v", headers["/folder/file"])
+	}
+
+	if getLocalFileMode(0400) != 0600 || getLocalFileMode(0755) != 0755 {
+		t.Fatalf("Unexpected local file modes %v and %v", getLocalFileMode(0400), getLocalFileMode(0755))
+	}
+}
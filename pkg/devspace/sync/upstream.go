@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/juju/errors"
@@ -25,6 +26,10 @@ type upstream struct {
 
 	symlinks map[string]*Symlink
 
+	// pendingUploads holds the local files that conflict resolution decided to upload
+	pendingUploads      map[string]*fileInformation
+	pendingUploadsMutex sync.Mutex
+
 	stdinPipe  io.WriteCloser
 	stdoutPipe io.ReadCloser
 	stderrPipe io.ReadCloser
@@ -137,6 +142,8 @@ func (u *upstream) mainLoop() error {
 				break
 			}
 
+			changes = append(changes, u.takePendingUploads()...)
+
 			// We gather changes till there are no more changes for 1 second
 			if changeAmount == len(changes) && changeAmount > 0 {
 				break
@@ -326,40 +333,50 @@ func (u *upstream) applyChanges(changes []*fileInformation) error {
 		}
 	}
 
+	uploadedBytes := int64(0)
 	if len(creates) > 0 {
 		// Apply creates
-		err := u.applyCreates(creates)
+		var err error
+
+		uploadedBytes, err = u.applyCreates(creates)
 		if err != nil {
 			return errors.Trace(err)
 		}
 	}
 
 	u.config.Logf("[Upstream] Successfully processed %d change(s)", len(changes))
+	u.config.emit(&Event{Type: EventUploaded, Changes: len(changes), Bytes: uploadedBytes})
 	return nil
 }
 
-func (u *upstream) applyCreates(files []*fileInformation) error {
+// applyCreates uploads the given files and returns the size of the uploaded archive
+func (u *upstream) applyCreates(files []*fileInformation) (int64, error) {
+	files, err := u.filterCreateConflicts(files)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+
 	filename, writtenFiles, err := writeTar(files, u.config)
 	if err != nil {
-		return errors.Trace(err)
+		return 0, errors.Trace(err)
 	}
 
 	// If we didn't write any files, we are done already
 	if len(writtenFiles) == 0 {
-		return nil
+		return 0, nil
 	}
 
 	// Open the archive
 	f, err := os.Open(filename)
 	if err != nil {
-		return errors.Trace(err)
+		return 0, errors.Trace(err)
 	}
 
 	defer f.Close()
 
 	stat, err := f.Stat()
 	if err != nil {
-		return errors.Trace(err)
+		return 0, errors.Trace(err)
 	}
 
 	// Print changes
@@ -373,7 +390,13 @@ func (u *upstream) applyCreates(files []*fileInformation) error {
 		}
 	}
 
-	return u.uploadArchive(f, strconv.Itoa(int(stat.Size())), writtenFiles)
+	err = u.uploadArchive(f, strconv.Itoa(int(stat.Size())), writtenFiles)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+
+	u.runOnUploadCommands(writtenFiles)
+	return stat.Size(), nil
 }
 
 func (u *upstream) uploadArchive(file *os.File, fileSize string, writtenFiles map[string]*fileInformation) error {
@@ -406,7 +429,7 @@ func (u *upstream) uploadArchive(file *os.File, fileSize string, writtenFiles ma
 							sleep 0.1;
 					done;
 
-					tar xzpf "$tmpFile" -C '` + u.config.DestPath + `/.' 2>/tmp/devspace-upstream-error;
+					` + getExtractCommand(u.config.transferCompression(), "$tmpFile", u.config.DestPath) + ` 2>/tmp/devspace-upstream-error;
 					echo "` + EndAck + `";
 		` // We need that extra new line or otherwise the command is not sent
 
@@ -459,6 +482,11 @@ func (u *upstream) uploadArchive(file *os.File, fileSize string, writtenFiles ma
 }
 
 func (u *upstream) applyRemoves(files []*fileInformation) error {
+	files, err := u.filterRemoveConflicts(files)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
 	u.config.fileIndex.fileMapMutex.Lock()
 	defer u.config.fileIndex.fileMapMutex.Unlock()
 
@@ -509,3 +537,118 @@ func (u *upstream) applyRemoves(files []*fileInformation) error {
 
 	return nil
 }
+
+// filterCreateConflicts removes the files from the list that changed in the container since the last sync
+// and where the container version wins the conflict. These files are overridden by downstream afterwards
+func (u *upstream) filterCreateConflicts(files []*fileInformation) ([]*fileInformation, error) {
+	remoteChanges, err := u.getRemoteChanges(files)
+	if err != nil || len(remoteChanges) == 0 {
+		return files, err
+	}
+
+	u.config.fileIndex.fileMapMutex.Lock()
+	defer u.config.fileIndex.fileMapMutex.Unlock()
+
+	filtered := make([]*fileInformation, 0, len(files))
+	for _, file := range files {
+		remote, ok := remoteChanges[file.Name]
+		if ok == false || u.config.resolveConflict(file.Name, file.Mtime, remote.Mtime) {
+			filtered = append(filtered, file)
+			continue
+		}
+
+		u.config.fileIndex.conflicts[file.Name] = true
+	}
+
+	return filtered, nil
+}
+
+// filterRemoveConflicts removes the files from the list that changed in the container since the last sync
+// and should not be removed. These files are downloaded again by downstream afterwards
+func (u *upstream) filterRemoveConflicts(files []*fileInformation) ([]*fileInformation, error) {
+	remoteChanges, err := u.getRemoteChanges(files)
+	if err != nil || len(remoteChanges) == 0 {
+		return files, err
+	}
+
+	u.config.fileIndex.fileMapMutex.Lock()
+	defer u.config.fileIndex.fileMapMutex.Unlock()
+
+	filtered := make([]*fileInformation, 0, len(files))
+	for _, file := range files {
+		_, ok := remoteChanges[file.Name]
+		if ok == false || u.config.resolveRemoveConflict(file.Name, false) {
+			filtered = append(filtered, file)
+			continue
+		}
+
+		// Forget the file, so that downstream downloads the container version again
+		delete(u.config.fileIndex.fileMap, file.Name)
+	}
+
+	return filtered, nil
+}
+
+// getRemoteChanges stats the tracked files of the given list in the container and returns the ones
+// that changed since the last sync
+func (u *upstream) getRemoteChanges(files []*fileInformation) (map[string]*fileInformation, error) {
+	remoteChanges := make(map[string]*fileInformation)
+	tracked := make([]string, 0, len(files))
+
+	u.config.fileIndex.fileMapMutex.Lock()
+	for _, file := range files {
+		synced := u.config.fileIndex.fileMap[file.Name]
+		if synced != nil && synced.IsDirectory == false && synced.IsSymbolicLink == false {
+			tracked = append(tracked, file.Name)
+		}
+	}
+	u.config.fileIndex.fileMapMutex.Unlock()
+
+	// Send stat commands with max 50 input args
+	for i := 0; i < len(tracked); i = i + 50 {
+		fileArguments := ""
+		for j := 0; j < 50 && i+j < len(tracked); j++ {
+			fileArguments += "'" + strings.Replace(u.config.DestPath+tracked[i+j], "'", "'\\''", -1) + "' "
+		}
+
+		statCommand := "stat -c \"%n///%s,%Y,%f,%a,%u,%g\" " + fileArguments + "2>/dev/null; "
+		if u.config.Checksum {
+			statCommand += "md5sum " + fileArguments + "2>/dev/null; "
+		}
+
+		statCommand += "echo \"" + EndAck + "\"\n"
+
+		_, err := u.stdinPipe.Write([]byte(statCommand))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+
+		output, err := readTill(EndAck, u.stdoutPipe)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+
+		checksums := parseChecksums(output, u.config.DestPath)
+
+		u.config.fileIndex.fileMapMutex.Lock()
+		for _, line := range strings.Split(output, "\n") {
+			if line == "" || line == EndAck || strings.Index(line, "///") == -1 {
+				continue
+			}
+
+			remote, err := parseFileInformation(line, u.config.DestPath)
+			if err != nil || remote == nil {
+				continue
+			}
+
+			remote.Checksum = checksums[remote.Name]
+
+			if hasChangedRemotely(remote, u.config) {
+				remoteChanges[remote.Name] = remote
+			}
+		}
+		u.config.fileIndex.fileMapMutex.Unlock()
+	}
+
+	return remoteChanges, nil
+}
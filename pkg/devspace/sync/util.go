@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/versions/latest"
 	"github.com/devspace-cloud/devspace/pkg/util/log"
 	"github.com/juju/errors"
 	gitignore "github.com/sabhiram/go-gitignore"
@@ -18,11 +19,11 @@ import (
 )
 
 // CopyToContainer copies a local folder to a container path
-func CopyToContainer(Kubectl kubernetes.Interface, Pod *k8sv1.Pod, Container *k8sv1.Container, LocalPath, ContainerPath string, ExcludePaths []string) error {
-	return copyToContainerTestable(Kubectl, Pod, Container, LocalPath, ContainerPath, ExcludePaths, false)
+func CopyToContainer(DevSpaceConfig *latest.Config, Kubectl kubernetes.Interface, Pod *k8sv1.Pod, Container *k8sv1.Container, LocalPath, ContainerPath string, ExcludePaths []string) error {
+	return copyToContainerTestable(DevSpaceConfig, Kubectl, Pod, Container, LocalPath, ContainerPath, ExcludePaths, false)
 }
 
-func copyToContainerTestable(Kubectl kubernetes.Interface, Pod *k8sv1.Pod, Container *k8sv1.Container, LocalPath, ContainerPath string, ExcludePaths []string, testing bool) error {
+func copyToContainerTestable(DevSpaceConfig *latest.Config, Kubectl kubernetes.Interface, Pod *k8sv1.Pod, Container *k8sv1.Container, LocalPath, ContainerPath string, ExcludePaths []string, testing bool) error {
 	stat, err := os.Lstat(LocalPath)
 
 	if err != nil {
@@ -46,14 +47,15 @@ func copyToContainerTestable(Kubectl kubernetes.Interface, Pod *k8sv1.Pod, Conta
 	}
 
 	s := &SyncConfig{
-		Kubectl:      Kubectl,
-		Pod:          Pod,
-		Container:    Container,
-		WatchPath:    getRelativeFromFullPath(LocalPath, ""),
-		DestPath:     ContainerPath,
-		ExcludePaths: ExcludePaths,
-		silent:       true,
-		testing:      testing,
+		DevSpaceConfig: DevSpaceConfig,
+		Kubectl:        Kubectl,
+		Pod:            Pod,
+		Container:      Container,
+		WatchPath:      getRelativeFromFullPath(LocalPath, ""),
+		DestPath:       ContainerPath,
+		ExcludePaths:   ExcludePaths,
+		silent:         true,
+		testing:        testing,
 	}
 
 	// syncLog = log.GetInstance()
@@ -65,7 +67,7 @@ func copyToContainerTestable(Kubectl kubernetes.Interface, Pod *k8sv1.Pod, Conta
 		return errors.Trace(err)
 	}
 
-	err = s.upstream.applyCreates([]*fileInformation{
+	_, err = s.upstream.applyCreates([]*fileInformation{
 		{
 			Name:        "",
 			IsDirectory: true,
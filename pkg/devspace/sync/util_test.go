@@ -18,7 +18,10 @@ func TestCopyToContainerTestable(t *testing.T) {
 		t.Skip("Skipping test on non linux platform")
 	}
 
-	remote, local, _ := initTestDirs(t)
+	remote, local, outside := initTestDirs(t)
+	defer os.RemoveAll(outside)
+	defer useTestLogdir(outside)()
+
 	excludePaths := []string{}
 
 	// Write local files
@@ -39,7 +42,7 @@ func TestCopyToContainerTestable(t *testing.T) {
 
 	ioutil.WriteFile(path.Join(local, "ignoredFolder", "testFile1"), []byte(fileContents), 0666)
 
-	err := copyToContainerTestable(nil, nil, nil, local, remote, excludePaths, true)
+	err := copyToContainerTestable(nil, nil, nil, nil, local, remote, excludePaths, true)
 	if err != nil {
 		t.Error(err)
 		return
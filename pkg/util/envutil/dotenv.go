@@ -0,0 +1,45 @@
+package envutil
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// ParseEnvFile parses a .env file with KEY=VALUE lines. Empty lines, comments and an export prefix
+// are ignored, values can be quoted with single or double quotes
+func ParseEnvFile(path string) (map[string]string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]string{}
+	for index, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+		separator := strings.Index(line, "=")
+		if separator <= 0 {
+			return nil, fmt.Errorf("Error parsing %s line %d: expected KEY=VALUE", path, index+1)
+		}
+
+		key := strings.TrimSpace(line[:separator])
+		value := strings.TrimSpace(line[separator+1:])
+		if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+			value = strings.Replace(value[1:len(value)-1], "\\n", "\n", -1)
+			value = strings.Replace(value, "\\\"", "\"", -1)
+		} else if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+			value = value[1 : len(value)-1]
+		} else if comment := strings.Index(value, " #"); comment >= 0 {
+			value = strings.TrimSpace(value[:comment])
+		}
+
+		values[key] = value
+	}
+
+	return values, nil
+}
@@ -21,6 +21,40 @@ func WriteToFile(data []byte, filePath string) error {
 	return ioutil.WriteFile(filePath, data, 0666)
 }
 
+// WriteFileAtomic writes data to a temporary file next to the file and renames it afterwards, so that
+// readers never see a partially written file
+func WriteFileAtomic(data []byte, filePath string, perm os.FileMode) error {
+	err := os.MkdirAll(filepath.Dir(filePath), 0755)
+	if err != nil {
+		return err
+	}
+
+	tempFile, err := ioutil.TempFile(filepath.Dir(filePath), "."+filepath.Base(filePath)+".tmp")
+	if err != nil {
+		return err
+	}
+
+	_, err = tempFile.Write(data)
+	if err == nil {
+		err = tempFile.Sync()
+	}
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tempFile.Name(), perm)
+	}
+	if err == nil {
+		err = os.Rename(tempFile.Name(), filePath)
+	}
+	if err != nil {
+		os.Remove(tempFile.Name())
+		return err
+	}
+
+	return nil
+}
+
 // ReadFile reads a file with a given limit
 func ReadFile(path string, limit int64) ([]byte, error) {
 	if limit <= 0 {
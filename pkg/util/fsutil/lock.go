@@ -0,0 +1,43 @@
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// FileLock is an advisory lock on a lock file next to a file. The file itself can't be locked, because
+// it is replaced on every atomic write
+type FileLock struct {
+	file *os.File
+}
+
+// Lock waits until the lock for the file at the given path is acquired
+func Lock(filePath string) (*FileLock, error) {
+	err := os.MkdirAll(filepath.Dir(filePath), 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(filePath+".lock", os.O_CREATE|os.O_RDWR, 0666)
+	if err != nil {
+		return nil, err
+	}
+
+	err = lockFile(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &FileLock{file: file}, nil
+}
+
+// Unlock releases the lock
+func (l *FileLock) Unlock() error {
+	err := unlockFile(l.file)
+	if closeErr := l.file.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
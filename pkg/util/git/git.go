@@ -6,6 +6,7 @@ import (
 
 	"github.com/pkg/errors"
 	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
 )
 
 // Repository holds the information about a repository
@@ -148,3 +149,30 @@ func (gr *Repository) Update() (bool, error) {
 
 	return true, nil
 }
+
+// Checkout checks out the given branch, tag or commit hash
+func (gr *Repository) Checkout(ref string) error {
+	repo, err := git.PlainOpen(gr.LocalPath)
+	if err != nil {
+		return errors.Wrap(err, "git open")
+	}
+
+	// Branches that are not checked out only exist as remote references
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		hash, err = repo.ResolveRevision(plumbing.Revision("origin/" + ref))
+		if err != nil {
+			return fmt.Errorf("Couldn't find ref %s in %s", ref, gr.RemotURL)
+		}
+	}
+
+	repoWorktree, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+
+	return repoWorktree.Checkout(&git.CheckoutOptions{
+		Hash:  *hash,
+		Force: true,
+	})
+}
@@ -0,0 +1,77 @@
+package ignoreutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gitignore "github.com/sabhiram/go-gitignore"
+)
+
+// GetGitIgnoreRules reads the rules of all .gitignore files within the root directory. Like git, the rules
+// of a .gitignore file only apply to its own folder and .gitignore files in ignored folders are skipped
+func GetGitIgnoreRules(rootDirectory string) ([]string, error) {
+	ignoreRules := []string{}
+
+	var ignoreParser gitignore.IgnoreParser
+	err := filepath.Walk(rootDirectory, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() == false {
+			return nil
+		}
+
+		relativePath := strings.Replace(strings.TrimPrefix(path, rootDirectory), "\\", "/", -1)
+		if info.Name() == ".git" || (ignoreParser != nil && relativePath != "" && ignoreParser.MatchesPath(relativePath)) {
+			return filepath.SkipDir
+		}
+
+		ignoreBytes, err := ioutil.ReadFile(filepath.Join(path, ".gitignore"))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+
+			return err
+		}
+
+		for _, ignoreRule := range strings.Split(string(ignoreBytes), "\n") {
+			ignoreRule = prefixGitIgnoreRule(strings.Trim(ignoreRule, "\r "), relativePath)
+			if ignoreRule != "" {
+				ignoreRules = append(ignoreRules, ignoreRule)
+			}
+		}
+
+		ignoreParser, err = gitignore.CompileIgnoreLines(ignoreRules...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ignoreRules, nil
+}
+
+// prefixGitIgnoreRule rewrites a rule of the .gitignore in pathPrefix so that it is relative to the root directory
+func prefixGitIgnoreRule(ignoreRule, pathPrefix string) string {
+	if len(ignoreRule) == 0 || ignoreRule[0] == '#' {
+		return ""
+	}
+
+	negate := ""
+	if ignoreRule[0] == '!' {
+		negate = "!"
+		ignoreRule = ignoreRule[1:]
+	}
+
+	// Rules that contain a slash (besides a trailing one) are relative to the .gitignore, all other rules match in any subfolder
+	if strings.Contains(strings.TrimSuffix(ignoreRule, "/"), "/") {
+		return negate + pathPrefix + "/" + strings.TrimPrefix(ignoreRule, "/")
+	} else if pathPrefix != "" {
+		return negate + pathPrefix + "/**/" + ignoreRule
+	}
+
+	return negate + ignoreRule
+}
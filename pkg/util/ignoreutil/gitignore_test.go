@@ -0,0 +1,73 @@
+package ignoreutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gitignore "github.com/sabhiram/go-gitignore"
+)
+
+func TestGetGitIgnoreRules(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		".gitignore":                  "# comment\nnode_modules/\n/build\nlogs/*.log\n",
+		"sub/.gitignore":              "*.tmp\n/out\n!keep.tmp\n",
+		"node_modules/lib/.gitignore": "*.js\n",
+	}
+	for name, content := range files {
+		err = os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ignoreRules, err := GetGitIgnoreRules(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"node_modules/", "/build", "/logs/*.log", "/sub/**/*.tmp", "/sub/out", "!/sub/**/keep.tmp"}
+	if len(ignoreRules) != len(expected) {
+		t.Fatalf("Expected rules %v, got %v", expected, ignoreRules)
+	}
+	for i := range expected {
+		if ignoreRules[i] != expected[i] {
+			t.Fatalf("Expected rules %v, got %v", expected, ignoreRules)
+		}
+	}
+
+	ignoreParser, err := gitignore.CompileIgnoreLines(ignoreRules...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	matches := map[string]bool{
+		"/build":              true,
+		"/sub/build":          false,
+		"/sub/a/b.tmp":        true,
+		"/sub/a/keep.tmp":     false,
+		"/a.tmp":              false,
+		"/sub/out":            true,
+		"/sub/a/out":          false,
+		"/node_modules/a.js":  true,
+		"/src/node_modules/x": true,
+	}
+	for path, expectedMatch := range matches {
+		if ignoreParser.MatchesPath(path) != expectedMatch {
+			t.Fatalf("Expected MatchesPath(%s) to be %v", path, expectedMatch)
+		}
+	}
+}
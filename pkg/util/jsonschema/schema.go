@@ -0,0 +1,158 @@
+package jsonschema
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Draft is the json schema draft the generated schemas conform to
+const Draft = "http://json-schema.org/draft-07/schema#"
+
+// VarPattern matches values that contain a config variable. Such values are allowed for every scalar
+// type, because they are replaced before the config is parsed
+const VarPattern = "\\$\\{[^\\}]+\\}"
+
+// Schema is a json schema
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Definitions          map[string]*Schema `json:"definitions,omitempty"`
+}
+
+// Generate creates a json schema for the given struct. Properties are named after the yaml tags of the
+// struct fields, fields without omitempty are required and nested structs are added as definitions.
+// Structs without any yaml tags are free-form objects
+func Generate(v interface{}, title string) *Schema {
+	definitions := map[string]*Schema{}
+
+	schema := generateType(reflect.TypeOf(v), definitions, true)
+	schema.Schema = Draft
+	schema.Title = title
+	if len(definitions) > 0 {
+		schema.Definitions = definitions
+	}
+
+	return schema
+}
+
+func generateType(t reflect.Type, definitions map[string]*Schema, root bool) *Schema {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return generateType(t.Elem(), definitions, root)
+	case reflect.Struct:
+		// Structs without yaml tags (e.g. kubernetes types) are parsed differently, so we allow any object
+		if hasYamlTags(t) == false {
+			return &Schema{Type: "object"}
+		}
+		if root {
+			return generateStruct(t, definitions)
+		}
+
+		if _, ok := definitions[t.Name()]; ok == false {
+			// Reserve the name first to support recursive types
+			definitions[t.Name()] = nil
+			definitions[t.Name()] = generateStruct(t, definitions)
+		}
+
+		return &Schema{Ref: "#/definitions/" + t.Name()}
+	case reflect.Slice, reflect.Array:
+		return &Schema{
+			Type:  "array",
+			Items: generateType(t.Elem(), definitions, false),
+		}
+	case reflect.Map:
+		return &Schema{
+			Type:                 "object",
+			AdditionalProperties: generateType(t.Elem(), definitions, false),
+		}
+	case reflect.String:
+		return anyScalar()
+	case reflect.Bool:
+		return scalarOrVar("boolean")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return scalarOrVar("integer")
+	case reflect.Float32, reflect.Float64:
+		return scalarOrVar("number")
+	}
+
+	// Interfaces and other types allow any value
+	return &Schema{}
+}
+
+func scalarOrVar(schemaType string) *Schema {
+	return &Schema{
+		AnyOf: []*Schema{
+			{Type: schemaType},
+			{Type: "string", Pattern: VarPattern},
+		},
+	}
+}
+
+// anyScalar allows every scalar for string fields, because yaml parses unquoted values like 0.1 or 1 as numbers
+func anyScalar() *Schema {
+	return &Schema{
+		AnyOf: []*Schema{
+			{Type: "string"},
+			{Type: "number"},
+			{Type: "integer"},
+			{Type: "boolean"},
+		},
+	}
+}
+
+func generateStruct(t reflect.Type, definitions map[string]*Schema) *Schema {
+	schema := &Schema{
+		Type:                 "object",
+		Properties:           map[string]*Schema{},
+		AdditionalProperties: false,
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		tag := strings.Split(field.Tag.Get("yaml"), ",")
+		name := tag[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+
+		omitEmpty := false
+		for _, option := range tag[1:] {
+			if option == "omitempty" {
+				omitEmpty = true
+			}
+		}
+
+		schema.Properties[name] = generateType(field.Type, definitions, false)
+		if omitEmpty == false {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	return schema
+}
+
+func hasYamlTags(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if _, ok := t.Field(i).Tag.Lookup("yaml"); ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,221 @@
+package jsonschema
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Error is a validation error of a single value
+type Error struct {
+	// Path is the path to the value, consisting of map keys (string) and list indices (int)
+	Path    []interface{}
+	Message string
+}
+
+// PathString returns the path in the form deployments[0].helm
+func (e *Error) PathString() string {
+	path := ""
+	for _, segment := range e.Path {
+		switch s := segment.(type) {
+		case int:
+			path += fmt.Sprintf("[%d]", s)
+		default:
+			if path != "" {
+				path += "."
+			}
+
+			path += fmt.Sprintf("%v", s)
+		}
+	}
+
+	return path
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	if len(e.Path) == 0 {
+		return e.Message
+	}
+
+	return e.PathString() + ": " + e.Message
+}
+
+// Validate validates the data (as returned by yaml.Unmarshal into an interface{}) against the schema
+// and returns all found errors
+func Validate(schema *Schema, data interface{}) []*Error {
+	v := &validator{root: schema}
+	v.validate(schema, data, []interface{}{})
+
+	return v.errors
+}
+
+type validator struct {
+	root   *Schema
+	errors []*Error
+}
+
+func (v *validator) addError(path []interface{}, format string, args ...interface{}) {
+	v.errors = append(v.errors, &Error{
+		Path:    append([]interface{}{}, path...),
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) validate(schema *Schema, data interface{}, path []interface{}) {
+	if schema.Ref != "" {
+		definition := v.root.Definitions[strings.TrimPrefix(schema.Ref, "#/definitions/")]
+		if definition == nil {
+			v.addError(path, "unresolvable reference %s", schema.Ref)
+			return
+		}
+
+		schema = definition
+	}
+
+	// Null values are allowed for every field
+	if data == nil {
+		return
+	}
+
+	if len(schema.AnyOf) > 0 {
+		var firstErrors []*Error
+		for index, alternative := range schema.AnyOf {
+			alternativeValidator := &validator{root: v.root}
+			alternativeValidator.validate(alternative, data, path)
+			if len(alternativeValidator.errors) == 0 {
+				return
+			}
+			if index == 0 {
+				firstErrors = alternativeValidator.errors
+			}
+		}
+
+		v.errors = append(v.errors, firstErrors...)
+		return
+	}
+
+	if schema.Type != "" && matchesType(schema.Type, data) == false {
+		v.addError(path, "expected %s, got %s", schema.Type, typeName(data))
+		return
+	}
+
+	if schema.Pattern != "" {
+		if str, ok := data.(string); ok && regexp.MustCompile(schema.Pattern).MatchString(str) == false {
+			v.addError(path, "value %q does not match %s", str, schema.Pattern)
+		}
+	}
+
+	if len(schema.Enum) > 0 {
+		found := false
+		for _, value := range schema.Enum {
+			if reflect.DeepEqual(value, data) {
+				found = true
+				break
+			}
+		}
+		if found == false {
+			v.addError(path, "value %v is not one of %v", data, schema.Enum)
+		}
+	}
+
+	switch typedData := data.(type) {
+	case map[interface{}]interface{}:
+		v.validateObject(schema, typedData, path)
+	case []interface{}:
+		if schema.Items != nil {
+			for index, item := range typedData {
+				v.validate(schema.Items, item, append(path, index))
+			}
+		}
+	}
+}
+
+func (v *validator) validateObject(schema *Schema, data map[interface{}]interface{}, path []interface{}) {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, fmt.Sprintf("%v", key))
+	}
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := lookup(data, key)
+		if property, ok := schema.Properties[key]; ok {
+			v.validate(property, value, append(path, key))
+		} else if additionalProperties, ok := schema.AdditionalProperties.(*Schema); ok {
+			v.validate(additionalProperties, value, append(path, key))
+		} else if schema.AdditionalProperties == false {
+			v.addError(append(path, key), "unknown field %s", key)
+		}
+	}
+
+	for _, required := range schema.Required {
+		if _, ok := data[required]; ok == false {
+			v.addError(path, "missing required field %s", required)
+		}
+	}
+}
+
+func lookup(data map[interface{}]interface{}, key string) interface{} {
+	if value, ok := data[key]; ok {
+		return value
+	}
+
+	for k, value := range data {
+		if fmt.Sprintf("%v", k) == key {
+			return value
+		}
+	}
+
+	return nil
+}
+
+func matchesType(schemaType string, data interface{}) bool {
+	switch schemaType {
+	case "object":
+		_, ok := data.(map[interface{}]interface{})
+		return ok
+	case "array":
+		_, ok := data.([]interface{})
+		return ok
+	case "string":
+		_, ok := data.(string)
+		return ok
+	case "boolean":
+		_, ok := data.(bool)
+		return ok
+	case "integer":
+		switch data.(type) {
+		case int, int64, uint64:
+			return true
+		}
+	case "number":
+		switch data.(type) {
+		case int, int64, uint64, float64:
+			return true
+		}
+	}
+
+	return false
+}
+
+func typeName(data interface{}) string {
+	switch data.(type) {
+	case map[interface{}]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case int, int64, uint64:
+		return "integer"
+	case float64:
+		return "number"
+	}
+
+	return fmt.Sprintf("%T", data)
+}
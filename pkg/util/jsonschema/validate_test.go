@@ -0,0 +1,62 @@
+package jsonschema
+
+import (
+	"testing"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+type testConfig struct {
+	Version  *string                 `yaml:"version"`
+	Replicas *int                    `yaml:"replicas,omitempty"`
+	Items    *[]*testItem            `yaml:"items,omitempty"`
+	Labels   *map[string]*string     `yaml:"labels,omitempty"`
+	Values   *map[string]interface{} `yaml:"values,omitempty"`
+}
+
+type testItem struct {
+	Name    *string `yaml:"name"`
+	Enabled *bool   `yaml:"enabled,omitempty"`
+}
+
+func TestValidate(t *testing.T) {
+	schema := Generate(&testConfig{}, "test")
+	if schema.Definitions["testItem"] == nil || schema.Properties["items"].Items.Ref != "#/definitions/testItem" {
+		t.Fatalf("Unexpected schema %#v", schema)
+	}
+
+	testCases := map[string][]string{
+		"version: v1\nreplicas: 2\nitems:\n- name: a\n  enabled: true\nlabels:\n  app: test\nvalues:\n  any: [1, 2]": []string{},
+		"version: v1\nreplicas: ${REPLICAS}\nitems:\n- name: a\n  enabled: ${ENABLED}":                               []string{},
+		"version: 0.1\nlabels:\n  tag: 1\n  debug: true":                                                             []string{},
+		"replicas: two\nunknown: true":                                                                               []string{"missing required field version", "replicas: expected integer, got string", "unknown: unknown field unknown"},
+		"version: v1\nitems:\n- enabled: 1":                                                                          []string{"items[0]: missing required field name", "items[0].enabled: expected boolean, got integer"},
+		"version: v1\nlabels:\n  app:\n    a: b":                                                                     []string{"labels.app: expected string, got object"},
+		"version: v1\nitems:\n  name: not a list":                                                                    []string{"items: expected array, got object"},
+	}
+
+	for content, expected := range testCases {
+		var data interface{}
+		err := yaml.Unmarshal([]byte(content), &data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		errors := Validate(schema, data)
+		if len(errors) != len(expected) {
+			t.Fatalf("Expected errors %v for %q, got %v", expected, content, errors)
+		}
+
+		for _, expectedError := range expected {
+			found := false
+			for _, err := range errors {
+				if err.Error() == expectedError {
+					found = true
+				}
+			}
+			if found == false {
+				t.Fatalf("Expected error %s for %q, got %v", expectedError, content, errors)
+			}
+		}
+	}
+}
@@ -26,6 +26,7 @@ func GetFileLogger(filename string) Logger {
 			logger: logrus.New(),
 		}
 		newLogger.logger.Formatter = &logrus.JSONFormatter{}
+		newLogger.logger.AddHook(&redactHook{})
 
 		os.MkdirAll(Logdir, os.ModePerm)
 
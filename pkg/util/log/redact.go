@@ -0,0 +1,93 @@
+package log
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RedactedValue replaces secret values in log messages
+const RedactedValue = "******"
+
+// MinRedactLength is the minimum length of values that are redacted. Shorter values like "1" or "on"
+// would be redacted everywhere in the output
+const MinRedactLength = 3
+
+var redactedValues = map[string]bool{}
+var redactedValuesMutex sync.RWMutex
+
+// Redact registers a secret value that should never be printed by any logger
+func Redact(value string) {
+	if len(value) < MinRedactLength {
+		return
+	}
+
+	redactedValuesMutex.Lock()
+	defer redactedValuesMutex.Unlock()
+
+	redactedValues[value] = true
+}
+
+func redact(message string) string {
+	redactedValuesMutex.RLock()
+	defer redactedValuesMutex.RUnlock()
+
+	for value := range redactedValues {
+		message = redactToken(message, value)
+	}
+
+	return message
+}
+
+// redactToken replaces all occurrences of value in message that are whole tokens, so that a secret
+// value like "dev" doesn't redact "devspace"
+func redactToken(message, value string) string {
+	if strings.Contains(message, value) == false {
+		return message
+	}
+
+	var out strings.Builder
+	last, start := 0, 0
+	for {
+		index := strings.Index(message[start:], value)
+		if index < 0 {
+			break
+		}
+
+		index += start
+		end := index + len(value)
+		if isTokenBoundary(message, index-1) && isTokenBoundary(message, end) {
+			out.WriteString(message[last:index])
+			out.WriteString(RedactedValue)
+			last, start = end, end
+		} else {
+			start = index + 1
+		}
+	}
+
+	out.WriteString(message[last:])
+	return out.String()
+}
+
+// isTokenBoundary checks if the character at index separates tokens
+func isTokenBoundary(message string, index int) bool {
+	if index < 0 || index >= len(message) {
+		return true
+	}
+
+	c := message[index]
+	return (c >= 'a' && c <= 'z') == false && (c >= 'A' && c <= 'Z') == false && (c >= '0' && c <= '9') == false && c != '_' && c < 0x80
+}
+
+// redactHook redacts secret values in messages of file loggers
+type redactHook struct{}
+
+func (r *redactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (r *redactHook) Fire(entry *logrus.Entry) error {
+	entry.Message = redact(entry.Message)
+	return nil
+}
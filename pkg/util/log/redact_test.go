@@ -0,0 +1,42 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestRedact(t *testing.T) {
+	Redact("secret-token")
+	defer delete(redactedValues, "secret-token")
+
+	buffer := &bytes.Buffer{}
+	logger := NewStreamLogger(buffer, logrus.InfoLevel)
+	logger.Infof("Using token %s", "secret-token")
+
+	if buffer.String() != "Info: Using token "+RedactedValue+"\n" {
+		t.Fatalf("Secret was not redacted: %s", buffer.String())
+	}
+}
+
+func TestRedactWholeTokens(t *testing.T) {
+	Redact("dev")
+	defer delete(redactedValues, "dev")
+
+	// Values that are too short are never redacted
+	Redact("1")
+
+	testCases := map[string]string{
+		"namespace dev":             "namespace " + RedactedValue,
+		"dev:dev,dev":               RedactedValue + ":" + RedactedValue + "," + RedactedValue,
+		"devspace dev-tools devdev": "devspace " + RedactedValue + "-tools devdev",
+		"replicas: 1":               "replicas: 1",
+	}
+
+	for message, expected := range testCases {
+		if redacted := redact(message); redacted != expected {
+			t.Fatalf("Expected %q to be redacted to %q, got %q", message, expected, redacted)
+		}
+	}
+}
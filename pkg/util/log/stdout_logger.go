@@ -95,7 +95,7 @@ func (s *stdoutLogger) writeMessage(fnType logFunctionType, message string) {
 		// fnInformation.stream.Write([]byte(fnInformation.tag))
 		// ct.ResetColor()
 
-		fnInformation.stream.Write([]byte(message))
+		fnInformation.stream.Write([]byte(redact(message)))
 
 		if s.loadingText != nil && fnType != fatalFn {
 			s.loadingText.Start()
@@ -491,7 +491,7 @@ func (s *stdoutLogger) WriteString(message string) {
 		s.loadingText.Stop()
 	}
 
-	fnTypeInformationMap[infoFn].stream.Write([]byte(message))
+	fnTypeInformationMap[infoFn].stream.Write([]byte(redact(message)))
 
 	if s.loadingText != nil {
 		s.loadingText.Start()
@@ -75,7 +75,7 @@ func (s *StreamLogger) writeMessage(fnType logFunctionType, message string) {
 			panic(err)
 		}
 
-		_, err = s.stream.Write([]byte(message))
+		_, err = s.stream.Write([]byte(redact(message)))
 		if err != nil {
 			panic(err)
 		}
@@ -0,0 +1,28 @@
+package portutil
+
+import (
+	"net"
+	"strconv"
+)
+
+// IsAvailable checks if the given local port can be bound on the given address
+func IsAvailable(address string, port int) bool {
+	listener, err := net.Listen("tcp", net.JoinHostPort(address, strconv.Itoa(port)))
+	if err != nil {
+		return false
+	}
+
+	listener.Close()
+	return true
+}
+
+// GetFreePort returns a local port on the given address that is currently not in use
+func GetFreePort(address string) (int, error) {
+	listener, err := net.Listen("tcp", net.JoinHostPort(address, "0"))
+	if err != nil {
+		return 0, err
+	}
+
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
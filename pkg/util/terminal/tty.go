@@ -35,3 +35,8 @@ func SetupTTY(stdin io.Reader, stdout io.Writer) term.TTY {
 
 	return t
 }
+
+// IsInteractive checks if stdin is a terminal, i.e. if the user can be asked questions
+func IsInteractive() bool {
+	return dockerterm.IsTerminal(os.Stdin.Fd())
+}
@@ -0,0 +1,134 @@
+package yamlutil
+
+import (
+	"regexp"
+	"strings"
+)
+
+var keyRegex = regexp.MustCompile(`^("[^"]*"|'[^']*'|[^\s'"#\-\[\{][^:#]*?|-[^\s:#][^:#]*?)\s*:(\s|$)`)
+
+// entry is a key or a list item within a block style yaml document
+type entry struct {
+	line   int
+	column int
+
+	listItem bool
+	key      string
+}
+
+// FindPosition returns the 1-based line and column of the value at the given path (map keys as string
+// and list indices as int) within the yaml content. Only block style yaml is supported, if the path
+// cannot be found the position of the deepest found parent is returned. Line and column are 0 if not even
+// the first path segment could be found
+func FindPosition(content []byte, path []interface{}) (int, int) {
+	entries := parseEntries(string(content))
+
+	line, column := 0, 0
+	start, end := 0, len(entries)
+	for _, segment := range path {
+		if start >= end {
+			break
+		}
+
+		indent := entries[start].column
+		found := -1
+		switch s := segment.(type) {
+		case int:
+			count := 0
+			for i := start; i < end; i++ {
+				if entries[i].column == indent && entries[i].listItem {
+					if count == s {
+						found = i
+						break
+					}
+
+					count++
+				}
+			}
+		case string:
+			for i := start; i < end; i++ {
+				if entries[i].column == indent && entries[i].listItem == false && entries[i].key == s {
+					found = i
+					break
+				}
+			}
+		}
+
+		if found == -1 {
+			break
+		}
+
+		line, column = entries[found].line+1, entries[found].column+1
+
+		// Find the end of the found block. Lists may start at the same indentation as their key
+		start = found + 1
+		end = findBlockEnd(entries, found, end)
+	}
+
+	return line, column
+}
+
+func findBlockEnd(entries []*entry, index int, end int) int {
+	for i := index + 1; i < end; i++ {
+		if entries[i].column < entries[index].column {
+			return i
+		}
+		if entries[i].column == entries[index].column {
+			if entries[index].listItem || entries[i].listItem == false {
+				return i
+			}
+		}
+	}
+
+	return end
+}
+
+func parseEntries(content string) []*entry {
+	entries := []*entry{}
+	blockScalarIndent := -1
+
+	for lineNumber, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		trimmed := strings.TrimLeft(line, " ")
+		column := len(line) - len(trimmed)
+
+		if trimmed == "" {
+			continue
+		}
+
+		// Skip the content of block scalars (| and >)
+		if blockScalarIndent >= 0 {
+			if column > blockScalarIndent {
+				continue
+			}
+
+			blockScalarIndent = -1
+		}
+
+		if strings.HasPrefix(trimmed, "#") || trimmed == "---" || trimmed == "..." {
+			continue
+		}
+
+		for trimmed == "-" || strings.HasPrefix(trimmed, "- ") {
+			entries = append(entries, &entry{line: lineNumber, column: column, listItem: true})
+
+			rest := strings.TrimLeft(trimmed[1:], " ")
+			column += len(trimmed) - len(rest)
+			trimmed = rest
+		}
+
+		match := keyRegex.FindStringSubmatch(trimmed)
+		if match == nil {
+			continue
+		}
+
+		entries = append(entries, &entry{line: lineNumber, column: column, key: strings.Trim(match[1], `"'`)})
+
+		value := strings.TrimSpace(trimmed[len(match[0]):])
+		if strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">") {
+			blockScalarIndent = column
+		}
+	}
+
+	return entries
+}
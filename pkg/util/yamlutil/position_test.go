@@ -0,0 +1,55 @@
+package yamlutil
+
+import "testing"
+
+const testYaml = `version: v1beta2
+# comment
+images:
+  default:
+    image: test
+    build:
+      docker: {}
+deployments:
+- name: frontend
+  helm:
+    values: |
+      name: not a key
+      other: value
+- name: backend
+  kubectl:
+    manifests:
+    - kube/*
+    flags:
+      - --force
+dev:
+  ports:
+    - selector: default
+      forward:
+      - port: 8080
+`
+
+func TestFindPosition(t *testing.T) {
+	testCases := []struct {
+		path   []interface{}
+		line   int
+		column int
+	}{
+		{path: []interface{}{"version"}, line: 1, column: 1},
+		{path: []interface{}{"images", "default", "build", "docker"}, line: 7, column: 7},
+		{path: []interface{}{"deployments", 1}, line: 14, column: 1},
+		{path: []interface{}{"deployments", 1, "name"}, line: 14, column: 3},
+		{path: []interface{}{"deployments", 1, "kubectl", "manifests", 0}, line: 17, column: 5},
+		{path: []interface{}{"deployments", 1, "kubectl", "flags", 0}, line: 19, column: 7},
+		{path: []interface{}{"deployments", 0, "helm", "values", "name"}, line: 11, column: 5},
+		{path: []interface{}{"dev", "ports", 0, "forward", 0, "port"}, line: 24, column: 9},
+		{path: []interface{}{"dev", "ports", 1}, line: 21, column: 3},
+		{path: []interface{}{"unknown"}, line: 0, column: 0},
+	}
+
+	for _, testCase := range testCases {
+		line, column := FindPosition([]byte(testYaml), testCase.path)
+		if line != testCase.line || column != testCase.column {
+			t.Fatalf("Expected %v at %d:%d, got %d:%d", testCase.path, testCase.line, testCase.column, line, column)
+		}
+	}
+}
@@ -64,3 +64,13 @@ for OS in ${DEVSPACE_BUILD_PLATFORMS[@]}; do
     fi
   done
 done
+
+# The sync agent is injected into linux containers only
+for ARCH in ${DEVSPACE_BUILD_ARCHS[@]}; do
+  NAME="devspace-sync-agent-linux-${ARCH}"
+
+  echo "Building sync agent for linux/${ARCH}"
+  GOARCH=${ARCH} GOOS=linux CGO_ENABLED=0 ${GO_BUILD_CMD} -ldflags "-s -w"\
+      -o "${DEVSPACE_ROOT}/release/${NAME}" ./sync-agent
+  shasum -a 256 "${DEVSPACE_ROOT}/release/${NAME}" > "${DEVSPACE_ROOT}/release/${NAME}".sha256
+done
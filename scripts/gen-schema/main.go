@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/versions"
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/versions/latest"
+)
+
+// Generates the json schemas of all config versions into the given folder
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Println("Usage: gen-schema OUTPUT_DIR")
+		os.Exit(1)
+	}
+
+	err := os.MkdirAll(os.Args[1], 0755)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	for _, version := range versions.GetVersions() {
+		err = writeSchema(version, filepath.Join(os.Args[1], version+".json"))
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
+	err = writeSchema(latest.Version, filepath.Join(os.Args[1], "latest.json"))
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func writeSchema(version, path string) error {
+	schema, err := versions.GetSchema(version)
+	if err != nil {
+		return err
+	}
+
+	out, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, append(out, '\n'), 0644)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/sync/agent"
+)
+
+// The sync agent is injected by the DevSpace CLI into the container and streams
+// file changes of the synchronized container path back to the downstream.
+// With --reverse-forward the agent listens on the given address instead and tunnels
+// all accepted connections over stdin and stdout to the DevSpace CLI.
+// The agent exits as soon as stdin is closed.
+func main() {
+	checksum := flag.Bool("checksum", false, "Append the md5 checksum of regular files to the change records")
+	reverseForward := flag.String("reverse-forward", "", "Listen on the given address (e.g. 127.0.0.1:8080) and tunnel connections over stdin and stdout")
+	flag.Parse()
+
+	if *reverseForward != "" {
+		listener, err := net.Listen("tcp", *reverseForward)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listening on %s: %v\n", *reverseForward, err)
+			os.Exit(1)
+		}
+
+		err = agent.ServeTunnel(listener, os.Stdin, os.Stdout)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error forwarding %s: %v\n", *reverseForward, err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: devspace-sync-agent [--checksum] PATH | --reverse-forward ADDRESS")
+		os.Exit(1)
+	}
+
+	path := flag.Arg(0)
+
+	watcher, err := agent.NewWatcher(path, os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating watcher: %v\n", err)
+		os.Exit(1)
+	}
+
+	watcher.Checksum = *checksum
+
+	stop := make(chan bool)
+	go func() {
+		_, _ = io.Copy(ioutil.Discard, os.Stdin)
+		close(stop)
+	}()
+
+	err = watcher.Watch(stop)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error watching %s: %v\n", path, err)
+		os.Exit(1)
+	}
+}